@@ -27,6 +27,7 @@ type Account struct {
 // Get retrieving a specific account information by ID
 // Reference: https://versafleet.docs.apiary.io/#reference/0/account-api/view-a-account
 func (s *Service) Get(ctx context.Context, id string) (*Account, error) {
+	ctx = client.WithOperation(ctx, "account.Get")
 	var account Account
 	path := fmt.Sprintf("/accounts/id")
 	err := s.client.Get(ctx, path, &account)
@@ -38,6 +39,7 @@ func (s *Service) Get(ctx context.Context, id string) (*Account, error) {
 
 // Create creates a new account
 func (s *Service) Create(ctx context.Context, account *Account) (*Account, error) {
+	ctx = client.WithOperation(ctx, "account.Create")
 	var createdAccount Account
 	err := s.client.Post(ctx, "/accounts", account, &createdAccount)
 	if err != nil {
@@ -48,6 +50,7 @@ func (s *Service) Create(ctx context.Context, account *Account) (*Account, error
 
 // Update updates an existing account
 func (s *Service) Update(ctx context.Context, id string, account *Account) (*Account, error) {
+	ctx = client.WithOperation(ctx, "account.Update")
 	var updatedAccount Account
 	path := fmt.Sprintf("/accounts/%s", id)
 	err := s.client.Put(ctx, path, account, &updatedAccount)
@@ -59,6 +62,7 @@ func (s *Service) Update(ctx context.Context, id string, account *Account) (*Acc
 
 // Delete deletes an account
 func (s *Service) Delete(ctx context.Context, id string) error {
+	ctx = client.WithOperation(ctx, "account.Delete")
 	path := fmt.Sprintf("/accounts/%s", id)
 	return s.client.Delete(ctx, path)
 }
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/config"
+	"github.com/go-resty/resty/v2"
+)
+
+// tokenRefreshSkew is how long before ExpiresAt a token is considered stale,
+// so that requests in flight don't race the expiry. Tokens living less than twice
+// as long are refreshed halfway through their lifetime.
+const tokenRefreshSkew = time.Minute
+
+type skipAuthKey struct{}
+
+// tokenResponse is the OAuth2 client-credentials token payload
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	CreatedAt   int64  `json:"created_at,omitempty"`
+}
+
+// Authenticate exchanges the client credentials for a bearer token and caches it.
+// A cached token that is still valid is reused. In query auth mode this is a no-op.
+// It is safe to call from multiple goroutines.
+func (c *Client) Authenticate(ctx context.Context) error {
+	if !c.usesOAuth2() {
+		return nil
+	}
+	_, err := c.accessToken(ctx)
+	return err
+}
+
+// usesOAuth2 is true unless query auth was asked for, matching Load's default
+func (c *Client) usesOAuth2() bool {
+	return c.config.AuthMode != config.AuthModeQuery
+}
+
+// accessToken returns a valid bearer token, fetching a new one if the cached token is missing or stale.
+// Concurrent callers block on the same refresh rather than each fetching their own token.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.Token != "" && (c.refreshAt.IsZero() || time.Now().Before(c.refreshAt)) {
+		return c.Token, nil
+	}
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	var token tokenResponse
+	resp, err := c.http.R().
+		SetContext(context.WithValue(ctx, skipAuthKey{}, true)).
+		SetFormData(map[string]string{
+			"grant_type":    "client_credentials",
+			"client_id":     c.config.ClientID,
+			"client_secret": c.config.ClientSecret,
+		}).
+		SetResult(&token).
+		ForceContentType("application/json").
+		Post(c.tokenURL())
+	if err != nil {
+		return "", fmt.Errorf("versafleet-sdk: token request failed: %w", err)
+	}
+	if resp.IsError() || token.AccessToken == "" {
+		return "", errors.New("versafleet-sdk: token response did not contain an access token")
+	}
+
+	issuedAt := time.Now()
+	if token.CreatedAt > 0 {
+		issuedAt = time.Unix(token.CreatedAt, 0)
+	}
+	c.Token = token.AccessToken
+	c.ExpiresAt, c.refreshAt = time.Time{}, time.Time{}
+	if token.ExpiresIn > 0 {
+		// Short-lived tokens are refreshed halfway through rather than straight away
+		lifetime := time.Duration(token.ExpiresIn) * time.Second
+		c.ExpiresAt = issuedAt.Add(lifetime)
+		c.refreshAt = c.ExpiresAt.Add(-min(tokenRefreshSkew, lifetime/2))
+	}
+	// Without expires_in the token is kept until the API rejects it
+	c.log.InfoContext(ctx, "versafleet-sdk: fetched access token", slog.Time("expires_at", c.ExpiresAt))
+	return c.Token, nil
+}
+
+func (c *Client) tokenURL() string {
+	if c.config.TokenURL == "" {
+		return config.DefaultTokenURL
+	}
+	return c.config.TokenURL
+}
+
+// invalidateToken drops the cached token so the next request fetches a new one.
+// Only the token that was rejected is dropped, in case another goroutine already refreshed it.
+func (c *Client) invalidateToken(rejected string) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	if c.Token == rejected {
+		c.Token = ""
+		c.ExpiresAt, c.refreshAt = time.Time{}, time.Time{}
+	}
+}
+
+// authorize is a resty request middleware that attaches the bearer token on every attempt
+func (c *Client) authorize(_ *resty.Client, req *resty.Request) error {
+	if skip, _ := req.Context().Value(skipAuthKey{}).(bool); skip {
+		return nil
+	}
+	token, err := c.accessToken(req.Context())
+	if err != nil {
+		return err
+	}
+	req.SetAuthToken(token)
+	return nil
+}
+
+// retryUnauthorized retries a request once after a 401, with the rejected token dropped
+func (c *Client) retryUnauthorized(resp *resty.Response) bool {
+	if skip, _ := resp.Request.Context().Value(skipAuthKey{}).(bool); skip || resp.Request.Attempt > 1 {
+		return false
+	}
+	c.log.InfoContext(resp.Request.Context(), "versafleet-sdk: access token rejected, retrying with a new one",
+		slog.String("operation", OperationFrom(resp.Request.Context())))
+	c.invalidateToken(resp.Request.Token)
+	return true
+}
@@ -0,0 +1,209 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/config"
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+	"github.com/Willias7788/go-versafleet-sdk/versafleettest"
+)
+
+func TestHandBuiltConfigDefaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      config.AuthMode
+		wantToken bool
+	}{
+		{"empty auth mode uses oauth2 like Load", "", true},
+		{"oauth2 without token url uses the default", config.AuthModeOAuth2, true},
+		{"query auth sends no token request", config.AuthModeQuery, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := versafleettest.NewServer()
+			defer srv.Close()
+
+			c := client.New(&config.Config{
+				BaseURL:      srv.URL,
+				ClientID:     versafleettest.ClientID,
+				ClientSecret: versafleettest.ClientSecret,
+				AuthMode:     tt.mode,
+			})
+			if err := c.Verify(context.Background()); err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+
+			var tokenRequests int
+			for _, r := range srv.Requests() {
+				if r.URL.Path == config.DefaultTokenURL {
+					tokenRequests++
+				}
+			}
+			if got := tokenRequests > 0; got != tt.wantToken {
+				t.Errorf("token requested = %v, want %v", got, tt.wantToken)
+			}
+			if !tt.wantToken && c.Token != "" {
+				t.Errorf("query auth fetched a token")
+			}
+		})
+	}
+}
+
+// tokenServer issues numbered tokens ("token-1", "token-2", ...) and serves {} to API calls
+// made with a token it hasn't been told to reject
+type tokenServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	expiresIn int64
+	age       time.Duration // How long ago each token claims to have been issued
+	reject    func(token string) bool
+	issued    int
+	apiCalls  int
+}
+
+func newTokenServer(expiresIn int64) *tokenServer {
+	s := &tokenServer{expiresIn: expiresIn}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func (s *tokenServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == config.DefaultTokenURL {
+		s.issued++
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d,"created_at":%d}`,
+			s.issued, s.expiresIn, time.Now().Add(-s.age).Unix())
+		return
+	}
+	s.apiCalls++
+	token := r.Header.Get("Authorization")
+	if token == "" || (s.reject != nil && s.reject(token[len("Bearer "):])) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"Unauthorized"}`))
+		return
+	}
+	w.Write([]byte(`{}`))
+}
+
+func (s *tokenServer) counts() (issued, apiCalls int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issued, s.apiCalls
+}
+
+func newTokenClient(srv *tokenServer) *client.Client {
+	return client.New(&config.Config{BaseURL: srv.URL, ClientID: "id", ClientSecret: "secret"},
+		client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100})),
+		client.WithRetryPolicy(fastPolicy()),
+	)
+}
+
+func TestTokenRefreshBeforeExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresIn int64
+		age       time.Duration
+		want      int
+	}{
+		{"valid token is reused", 7200, 0, 1},
+		{"token within a minute of expiry is refreshed", 7200, 7200*time.Second - 30*time.Second, 3},
+		{"token without expires_in is kept", 0, 0, 1},
+		{"short-lived token is kept until halfway", 60, 0, 1},
+		{"short-lived token past halfway is refreshed", 60, 40 * time.Second, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTokenServer(tt.expiresIn)
+			defer srv.Close()
+			srv.age = tt.age
+
+			c := newTokenClient(srv)
+			for range 3 {
+				if err := c.Authenticate(context.Background()); err != nil {
+					t.Fatalf("Authenticate: %v", err)
+				}
+			}
+			if issued, _ := srv.counts(); issued != tt.want {
+				t.Errorf("%d tokens fetched, want %d", issued, tt.want)
+			}
+			if tt.expiresIn == 0 && !c.ExpiresAt.IsZero() {
+				t.Errorf("ExpiresAt = %v, want zero without expires_in", c.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestTokenRefreshOnUnauthorized(t *testing.T) {
+	tests := []struct {
+		name         string
+		reject       func(token string) bool
+		wantErr      bool
+		wantIssued   int
+		wantAPICalls int
+	}{
+		{"rejected token is replaced once", func(token string) bool { return token == "token-1" }, false, 2, 2},
+		{"second rejection is returned", func(string) bool { return true }, true, 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTokenServer(7200)
+			defer srv.Close()
+			srv.reject = tt.reject
+
+			err := newTokenClient(srv).Get(context.Background(), "/v2/jobs", &map[string]interface{}{})
+			var apiErr *client.APIError
+			if tt.wantErr {
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+					t.Errorf("err = %v, want a 401 *APIError", err)
+				}
+			} else if err != nil {
+				t.Errorf("Get: %v", err)
+			}
+			if issued, apiCalls := srv.counts(); issued != tt.wantIssued || apiCalls != tt.wantAPICalls {
+				t.Errorf("%d tokens fetched and %d API calls, want %d and %d", issued, apiCalls, tt.wantIssued, tt.wantAPICalls)
+			}
+		})
+	}
+}
+
+func TestTokenConcurrentUse(t *testing.T) {
+	srv := newTokenServer(7200)
+	defer srv.Close()
+	c := newTokenClient(srv)
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*n)
+	for range n {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs <- c.Authenticate(context.Background())
+		}()
+		go func() {
+			defer wg.Done()
+			errs <- c.Get(context.Background(), "/v2/jobs", &map[string]interface{}{})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent call: %v", err)
+		}
+	}
+	if issued, apiCalls := srv.counts(); issued != 1 || apiCalls != n {
+		t.Errorf("%d tokens fetched and %d API calls, want 1 and %d", issued, apiCalls, n)
+	}
+}
@@ -0,0 +1,390 @@
+// Package cassette records VersaFleet API traffic to a file and replays it, so tests
+// can run against real responses without network access or credentials.
+//
+//	rec, err := cassette.Open("testdata/list_tasks.json", cassette.ModeReplay)
+//	c := client.New(cfg, client.WithTransport(rec))
+//
+// Record once with ModeRecord against the real API, commit the cassette, and replay
+// it in CI. Client secrets and bearer tokens are redacted before anything is written.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/internal/atomicfile"
+)
+
+// Redacted replaces secrets in recorded interactions
+const Redacted = "REDACTED"
+
+// Mode selects between recording and replaying
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and never touches the network
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the API and writes every interaction to the cassette,
+	// replacing what was there
+	ModeRecord
+)
+
+// Secret query parameters, form fields and JSON fields
+var secretParams = map[string]bool{
+	"client_secret": true,
+	"access_token":  true,
+	"refresh_token": true,
+}
+
+// Secret headers
+var secretHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// Cassette is the file format: the interactions in the order they were recorded
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// UnmatchedError is returned in replay mode for a request that has no unused recorded interaction
+type UnmatchedError struct {
+	Method string
+	Path   string
+	Query  string
+	Body   string
+}
+
+func (e *UnmatchedError) Error() string {
+	msg := fmt.Sprintf("cassette: no recorded interaction matches %s %s", e.Method, e.Path)
+	if e.Query != "" {
+		msg += "?" + e.Query
+	}
+	if e.Body != "" {
+		msg += " with body " + e.Body
+	}
+	return msg
+}
+
+// Recorder is an http.RoundTripper that records to or replays from a cassette file
+type Recorder struct {
+	// Transport sends requests in ModeRecord. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	path string
+	mode Mode
+
+	mu        sync.Mutex
+	cassette  Cassette
+	used      []bool
+	unmatched []*UnmatchedError
+}
+
+// Open loads the cassette at path for ModeReplay, or starts an empty one for ModeRecord
+func Open(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("cassette: failed to decode %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Mode returns the mode the recorder was opened with
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	u := *req.URL
+	u.RawQuery = redactValues(u.Query()).Encode()
+	interaction := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     u.String(),
+			Headers: redactHeaders(req.Header),
+			Body:    redactBody(req.Header.Get("Content-Type"), body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    redactHeaders(resp.Header),
+			Body:       redactBody(resp.Header.Get("Content-Type"), respBody),
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	query := normalizeQuery(req.URL.RawQuery)
+	normBody := normalizeBody(req.Header.Get("Content-Type"), body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || !matches(in.Request, req.Method, req.URL.Path, query, normBody) {
+			continue
+		}
+		r.used[i] = true
+		header := in.Response.Headers.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		header.Del("Content-Length") // Redaction may have changed the body length
+		body := refreshTokenResponse(in.Response.Body)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	err := &UnmatchedError{Method: req.Method, Path: req.URL.Path, Query: query, Body: normBody}
+	r.unmatched = append(r.unmatched, err)
+	return nil, err
+}
+
+// refreshTokenResponse moves the created_at of a recorded token response to now,
+// so a replayed token isn't already expired and the client doesn't ask for another one
+func refreshTokenResponse(body string) string {
+	var token map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &token); err != nil {
+		return body
+	}
+	if _, ok := token["access_token"]; !ok {
+		return body
+	}
+	if _, ok := token["created_at"]; !ok {
+		return body
+	}
+	token["created_at"] = time.Now().Unix()
+	out, err := json.Marshal(token)
+	if err != nil {
+		return body
+	}
+	return string(out)
+}
+
+// Unmatched returns the replayed requests that had no recorded interaction.
+// Check it at the end of a test; the client may have retried or swallowed the error.
+func (r *Recorder) Unmatched() []*UnmatchedError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*UnmatchedError(nil), r.unmatched...)
+}
+
+// Unused returns the recorded interactions that were never replayed
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Interaction
+	for i, in := range r.cassette.Interactions {
+		if !r.used[i] {
+			out = append(out, in)
+		}
+	}
+	return out
+}
+
+// Err reports unmatched requests as a single error, or nil if every request matched
+func (r *Recorder) Err() error {
+	var errs []error
+	for _, u := range r.Unmatched() {
+		errs = append(errs, u)
+	}
+	return errors.Join(errs...)
+}
+
+func matches(rec Request, method, path, query, body string) bool {
+	if rec.Method != method {
+		return false
+	}
+	u, err := url.Parse(rec.URL)
+	if err != nil || u.Path != path {
+		return false
+	}
+	contentType := ""
+	if rec.Headers != nil {
+		contentType = rec.Headers.Get("Content-Type")
+	}
+	return normalizeQuery(u.RawQuery) == query && normalizeBody(contentType, []byte(rec.Body)) == body
+}
+
+// save writes the cassette through a temporary file and a rename. Callers hold r.mu.
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(&r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("cassette: failed to save %s: %w", r.path, err)
+	}
+	if err := atomicfile.Write(r.path, data); err != nil {
+		return fmt.Errorf("cassette: failed to save %s: %w", r.path, err)
+	}
+	return nil
+}
+
+// readBody reads the request body and puts it back, so it can still be sent
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// normalizeQuery redacts secrets and sorts parameters
+func normalizeQuery(raw string) string {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return raw
+	}
+	return redactValues(values).Encode()
+}
+
+// normalizeBody redacts secrets, sorts form fields and re-encodes JSON with sorted keys
+func normalizeBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	return redactBody(contentType, body)
+}
+
+func redactValues(values url.Values) url.Values {
+	out := make(url.Values, len(values))
+	for k, v := range values {
+		if secretParams[k] {
+			v = []string{Redacted}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range secretHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, Redacted)
+		}
+	}
+	return out
+}
+
+func redactBody(contentType string, body []byte) string {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return redactValues(values).Encode()
+		}
+	}
+
+	// UseNumber keeps large IDs exact
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return string(body)
+	}
+	out, err := json.Marshal(redactJSON(v))
+	if err != nil {
+		return string(body)
+	}
+	return string(out)
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if secretParams[k] {
+				v[k] = Redacted
+			} else {
+				v[k] = redactJSON(child)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactJSON(child)
+		}
+	}
+	return v
+}
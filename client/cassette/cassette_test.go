@@ -0,0 +1,157 @@
+package cassette_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/client/cassette"
+	"github.com/Willias7788/go-versafleet-sdk/customers"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+	"github.com/Willias7788/go-versafleet-sdk/versafleettest"
+)
+
+func newService(srv *versafleettest.Server, rec *cassette.Recorder) *customers.Service {
+	return customers.New(client.New(srv.Config(),
+		client.WithTransport(rec),
+		client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100})),
+		client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}),
+	))
+}
+
+// record runs a Get for a customer against the fake with a recording cassette, and returns the customer's ID
+func record(t *testing.T, path string) (*versafleettest.Server, int) {
+	t.Helper()
+	srv := versafleettest.NewServer()
+	customer := srv.AddCustomer(model.Customer{Name: "ACME", ContactNumber: "+65 6123 4567"})
+
+	rec, err := cassette.Open(path, cassette.ModeRecord)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	got, err := newService(srv, rec).Get(context.Background(), strconv.Itoa(customer.ID))
+	if err != nil {
+		t.Fatalf("Get while recording: %v", err)
+	}
+	if got.Name != "ACME" {
+		t.Fatalf("Get while recording = %+v", got.Customer)
+	}
+	return srv, customer.ID
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "customer.json")
+	srv, id := record(t, path)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "customer.json" {
+		t.Errorf("cassette dir holds %v, want only customer.json", entries)
+	}
+
+	// Replay doesn't touch the network, so the fake can go
+	srv.Close()
+	rec, err := cassette.Open(path, cassette.ModeReplay)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	got, err := newService(srv, rec).Get(context.Background(), strconv.Itoa(id))
+	if err != nil {
+		t.Fatalf("Get while replaying: %v", err)
+	}
+	if got.Name != "ACME" || got.ContactNumber != "+65 6123 4567" {
+		t.Errorf("replayed customer = %+v", got.Customer)
+	}
+	if err := rec.Err(); err != nil {
+		t.Errorf("Err = %v", err)
+	}
+	if unused := rec.Unused(); len(unused) != 0 {
+		t.Errorf("%d interactions were not replayed", len(unused))
+	}
+}
+
+func TestReplayUnmatchedRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "customer.json")
+	srv, id := record(t, path)
+	srv.Close()
+
+	rec, err := cassette.Open(path, cassette.ModeReplay)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	svc := newService(srv, rec)
+	if _, err := svc.Get(context.Background(), strconv.Itoa(id+1)); err == nil {
+		t.Fatal("Get for an unrecorded customer succeeded")
+	}
+
+	unmatched := rec.Unmatched()
+	if len(unmatched) != 1 {
+		t.Fatalf("got %d unmatched requests, want 1", len(unmatched))
+	}
+	want := "/customers/" + strconv.Itoa(id+1)
+	if unmatched[0].Method != http.MethodGet || unmatched[0].Path != want {
+		t.Errorf("unmatched = %s %s, want GET %s", unmatched[0].Method, unmatched[0].Path, want)
+	}
+	var unmatchedErr *cassette.UnmatchedError
+	if err := rec.Err(); !errors.As(err, &unmatchedErr) {
+		t.Errorf("Err = %v, want an *UnmatchedError", err)
+	}
+
+	// Each recorded interaction is only replayed once
+	if _, err := svc.Get(context.Background(), strconv.Itoa(id)); err != nil {
+		t.Fatalf("Get for the recorded customer: %v", err)
+	}
+	if _, err := svc.Get(context.Background(), strconv.Itoa(id)); err == nil {
+		t.Error("second Get replayed the same interaction again")
+	}
+}
+
+func TestOpenMissingCassette(t *testing.T) {
+	if _, err := cassette.Open(filepath.Join(t.TempDir(), "missing.json"), cassette.ModeReplay); err == nil {
+		t.Error("Open of a missing cassette in replay mode succeeded")
+	}
+}
+
+func TestRecordingScrubsSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "customer.json")
+	srv, _ := record(t, path)
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{versafleettest.ClientSecret, versafleettest.AccessToken} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q", secret)
+		}
+	}
+
+	rec, err := cassette.Open(path, cassette.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interactions := rec.Unused()
+	if len(interactions) != 2 {
+		t.Fatalf("got %d interactions, want the token request and the Get", len(interactions))
+	}
+	token, get := interactions[0], interactions[1]
+	if !strings.Contains(token.Request.Body, "client_secret="+cassette.Redacted) {
+		t.Errorf("token request body = %s", token.Request.Body)
+	}
+	if !strings.Contains(token.Response.Body, `"access_token":"`+cassette.Redacted+`"`) {
+		t.Errorf("token response body = %s", token.Response.Body)
+	}
+	if got := get.Request.Headers.Get("Authorization"); got != cassette.Redacted {
+		t.Errorf("Authorization = %q, want %q", got, cassette.Redacted)
+	}
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/Willias7788/go-versafleet-sdk/internal/atomicfile"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+)
+
+// Checkpoint is a serializable position within a paginated listing
+type Checkpoint struct {
+	Path    string          `json:"path"`
+	Page    int             `json:"page"`
+	Index   int             `json:"index"`   // Items of Page already consumed
+	Options json.RawMessage `json:"options"` // The list options, JSON encoded
+}
+
+// Checkpointer persists iterator progress so a listing can be resumed later
+type Checkpointer interface {
+	// Save stores the checkpoint, replacing any previous one
+	Save(ctx context.Context, cp *Checkpoint) error
+	// Load returns the stored checkpoint, or nil if there is none
+	Load(ctx context.Context) (*Checkpoint, error)
+	// Clear removes the stored checkpoint
+	Clear(ctx context.Context) error
+}
+
+// Checkpoint returns the iterator's current position.
+// Resuming from it continues with the item after the last one returned by Next.
+func (it *Iterator[T, O]) Checkpoint() (*Checkpoint, error) {
+	opts, err := json.Marshal(it.listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("versafleet-sdk: failed to encode list options: %w", err)
+	}
+	cp := &Checkpoint{
+		Path:    it.path,
+		Page:    it.listOptions.GetPage(),
+		Index:   it.currentIndex,
+		Options: opts,
+	}
+	if !it.started {
+		cp.Index = it.skip
+	}
+	return cp, nil
+}
+
+// WithCheckpointer saves a checkpoint to cp every time a new page is loaded, before
+// any of its items are returned, and clears it once the listing is exhausted.
+// A job resumed from the saved checkpoint therefore re-processes at most one page.
+func (it *Iterator[T, O]) WithCheckpointer(cp Checkpointer) *Iterator[T, O] {
+	it.checkpointer = cp
+	return it
+}
+
+func (it *Iterator[T, O]) saveCheckpoint() error {
+	cp, err := it.Checkpoint()
+	if err != nil {
+		return err
+	}
+	return it.checkpointer.Save(it.ctx, cp)
+}
+
+// finish is called when the listing is exhausted without error
+func (it *Iterator[T, O]) finish() {
+	if it.checkpointer != nil {
+		if err := it.checkpointer.Clear(it.ctx); err != nil {
+			it.err = err
+		}
+	}
+	it.Close()
+}
+
+// ResumeIterator creates an iterator that continues from a checkpoint.
+// The options stored in the checkpoint are decoded into a new O.
+func ResumeIterator[T any, O model.Paginatable](
+	ctx context.Context,
+	client *Client,
+	cp *Checkpoint,
+	fetchFunc func(context.Context, string, O) ([]T, *model.Meta, error),
+) (*Iterator[T, O], error) {
+	if cp == nil {
+		return nil, errors.New("versafleet-sdk: nil checkpoint")
+	}
+
+	var opts O
+	if t := reflect.TypeOf(opts); t != nil && t.Kind() == reflect.Ptr {
+		opts = reflect.New(t.Elem()).Interface().(O)
+	}
+	if len(cp.Options) > 0 {
+		if err := json.Unmarshal(cp.Options, opts); err != nil {
+			return nil, fmt.Errorf("versafleet-sdk: failed to decode checkpoint options: %w", err)
+		}
+	}
+	opts.SetPage(cp.Page)
+
+	it := NewIterator(ctx, client, cp.Path, opts, fetchFunc)
+	it.skip = cp.Index
+	return it, nil
+}
+
+// MemoryCheckpointer keeps the checkpoint in memory. Useful for tests and for
+// retrying within a single process.
+type MemoryCheckpointer struct {
+	mu sync.Mutex
+	cp *Checkpoint
+}
+
+func (m *MemoryCheckpointer) Save(_ context.Context, cp *Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	saved := *cp
+	m.cp = &saved
+	return nil
+}
+
+func (m *MemoryCheckpointer) Load(_ context.Context) (*Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cp == nil {
+		return nil, nil
+	}
+	loaded := *m.cp
+	return &loaded, nil
+}
+
+func (m *MemoryCheckpointer) Clear(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cp = nil
+	return nil
+}
+
+// FileCheckpointer stores the checkpoint as JSON in a file.
+// Writes go through a temporary file and a rename, so a crash never leaves a torn checkpoint.
+type FileCheckpointer struct {
+	Path string
+}
+
+func (f *FileCheckpointer) Save(_ context.Context, cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.Write(f.Path, data); err != nil {
+		return fmt.Errorf("versafleet-sdk: failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (f *FileCheckpointer) Load(_ context.Context) (*Checkpoint, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("versafleet-sdk: failed to load checkpoint: %w", err)
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("versafleet-sdk: failed to decode checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+func (f *FileCheckpointer) Clear(_ context.Context) error {
+	if err := os.Remove(f.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+)
+
+// recordingCheckpointer is a MemoryCheckpointer that also keeps every saved position
+type recordingCheckpointer struct {
+	client.MemoryCheckpointer
+
+	mu    sync.Mutex
+	saves [][2]int // page, index
+}
+
+func (r *recordingCheckpointer) Save(ctx context.Context, cp *client.Checkpoint) error {
+	r.mu.Lock()
+	r.saves = append(r.saves, [2]int{cp.Page, cp.Index})
+	r.mu.Unlock()
+	return r.MemoryCheckpointer.Save(ctx, cp)
+}
+
+// drain returns the rest of the iterator's items
+func drain(it *client.Iterator[int, *model.ListOptions]) ([]int, error) {
+	var got []int
+	for it.Next() {
+		got = append(got, it.Value())
+	}
+	return got, it.Err()
+}
+
+func TestCheckpointerSavesEachPageAndClears(t *testing.T) {
+	f := &fakePages{totalPages: 3, perPage: 2}
+	cp := &recordingCheckpointer{}
+	got, err := drain(f.iterator(context.Background()).WithCheckpointer(cp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, 1, 2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+	if want := [][2]int{{1, 0}, {2, 0}, {3, 0}}; !reflect.DeepEqual(cp.saves, want) {
+		t.Errorf("saved page/index %v, want %v", cp.saves, want)
+	}
+	if saved, _ := cp.Load(context.Background()); saved != nil {
+		t.Errorf("checkpoint %+v left after the listing finished", saved)
+	}
+}
+
+func TestCheckpointerKeptOnError(t *testing.T) {
+	f := &fakePages{totalPages: 3, perPage: 2}
+	boom := errors.New("boom")
+	fetch := func(ctx context.Context, path string, opts *model.ListOptions) ([]int, *model.Meta, error) {
+		if opts.GetPage() == 3 {
+			return nil, nil, boom
+		}
+		return f.fetch(ctx, path, opts)
+	}
+	cp := &client.MemoryCheckpointer{}
+	it := client.NewIterator(context.Background(), nil, "/items", &model.ListOptions{PerPage: 2}, fetch).WithCheckpointer(cp)
+
+	got, err := drain(it)
+	if !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want %v", err, boom)
+	}
+	if len(got) != 4 {
+		t.Errorf("got %v before the error, want the first two pages", got)
+	}
+	saved, err := cp.Load(context.Background())
+	if err != nil || saved == nil {
+		t.Fatalf("Load = %+v, %v, want the last page's checkpoint", saved, err)
+	}
+	if saved.Page != 2 || saved.Index != 0 {
+		t.Errorf("checkpoint at page %d index %d, want page 2 index 0", saved.Page, saved.Index)
+	}
+}
+
+func TestResumeIterator(t *testing.T) {
+	f := &fakePages{totalPages: 3, perPage: 2}
+	it := f.iterator(context.Background())
+	for range 3 { // page 1 and the first item of page 2
+		if !it.Next() {
+			t.Fatal(it.Err())
+		}
+	}
+	cp, err := it.Checkpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Path != "/items" || cp.Page != 2 || cp.Index != 1 {
+		t.Fatalf("checkpoint = %+v, want /items page 2 index 1", cp)
+	}
+
+	resumed := &fakePages{totalPages: 3, perPage: 2}
+	var perPage []int
+	fetch := func(ctx context.Context, path string, opts *model.ListOptions) ([]int, *model.Meta, error) {
+		perPage = append(perPage, opts.GetPerPage())
+		return resumed.fetch(ctx, path, opts)
+	}
+	it2, err := client.ResumeIterator(context.Background(), nil, cp, fetch)
+	if err != nil {
+		t.Fatalf("ResumeIterator: %v", err)
+	}
+	got, err := drain(it2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("resumed items = %v, want %v", got, want)
+	}
+	if want := []int{2, 3}; !reflect.DeepEqual(resumed.fetched, want) {
+		t.Errorf("resumed fetched pages %v, want %v", resumed.fetched, want)
+	}
+	for _, n := range perPage {
+		if n != 2 {
+			t.Errorf("resumed with per_page %d, want the checkpoint's 2", n)
+		}
+	}
+
+	if _, err := client.ResumeIterator(context.Background(), nil, nil, fetch); err == nil {
+		t.Error("ResumeIterator accepted a nil checkpoint")
+	}
+}
+
+func TestFileCheckpointer(t *testing.T) {
+	ctx := context.Background()
+	f := &client.FileCheckpointer{Path: filepath.Join(t.TempDir(), "checkpoint.json")}
+
+	if cp, err := f.Load(ctx); cp != nil || err != nil {
+		t.Fatalf("Load of a missing file = %+v, %v, want nil, nil", cp, err)
+	}
+	if err := f.Clear(ctx); err != nil {
+		t.Errorf("Clear of a missing file: %v", err)
+	}
+
+	want := &client.Checkpoint{Path: "/v2/jobs", Page: 4, Index: 7, Options: []byte(`{"page":4,"per_page":50}`)}
+	if err := f.Save(ctx, want); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, err := f.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load = %+v, want %+v", got, want)
+	}
+
+	if err := f.Clear(ctx); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if cp, err := f.Load(ctx); cp != nil || err != nil {
+		t.Errorf("Load after Clear = %+v, %v, want nil, nil", cp, err)
+	}
+
+	if err := os.WriteFile(f.Path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Load(ctx); err == nil {
+		t.Error("Load of a corrupt checkpoint succeeded")
+	}
+}
@@ -4,68 +4,111 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/Willias7788/go-versafleet-sdk/config"
 	"github.com/Willias7788/go-versafleet-sdk/rate"
 	"github.com/go-resty/resty/v2"
-	ratelimit "golang.org/x/time/rate"
 )
 
 type Client struct {
-	http      *resty.Client
-	config    *config.Config
-	limiter   *ratelimit.Limiter
-	Token     string
-	ExpiresAt time.Time
+	http        *resty.Client
+	config      *config.Config
+	limiter     rate.Limiter
+	retry       RetryPolicy
+	idempotency IdempotencyStore
+	middleware  []Middleware
+	logger      *slog.Logger // As given to WithLogger
+	piiFields   []string
+	log         *slog.Logger // logger with redaction, see configureLogging
+	settings    httpSettings
+	opTimeouts  map[string]time.Duration
+	configErr   error // First setting that couldn't be applied
+	authMu      sync.Mutex
+	Token       string    // Current bearer token, managed by Authenticate
+	ExpiresAt   time.Time // Expiry of Token, zero if the API gave none
+	refreshAt   time.Time // When Token is considered stale, zero for never
 }
 
-// New creates a new VersaFleet API client
-func New(cfg *config.Config) *Client {
-	r := resty.New()
-	r.SetBaseURL(cfg.BaseURL)
-	r.SetTimeout(time.Minute) // Default timeout
-
-	if cfg.Debug {
-		r.SetDebug(true)
+// New creates a new VersaFleet API client. HTTP settings come from cfg and can be overridden
+// with options. A setting that can't be applied (e.g. an unreadable CA file) is logged and
+// returned by every request made with the client.
+func New(cfg *config.Config, opts ...Option) *Client {
+	c := &Client{
+		config:  cfg,
+		limiter: rate.DefaultAdaptive(),
+		retry:   DefaultRetryPolicy(),
+	}
+	c.applyConfig()
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.configureLogging()
+
+	r := c.newResty()
+	c.http = r
+	// Resty logs to stderr by default; its messages go through the SDK logger instead
+	r.SetLogger(restyLogger{c})
+	if c.configErr != nil {
+		c.log.Error("versafleet-sdk: invalid client settings", slog.Any("error", c.configErr))
 	}
 
-	// User indicated that auth might be via query params
-	r.SetQueryParam("client_id", cfg.ClientID)
-	r.SetQueryParam("client_secret", cfg.ClientSecret)
+	// Fail every request if the settings were invalid, rather than send it without them
+	r.OnBeforeRequest(func(_ *resty.Client, _ *resty.Request) error {
+		return c.configErr
+	})
 
-	c := &Client{
-		http:    r,
-		config:  cfg,
-		limiter: rate.Default(),
+	// Retries skip R(), so they wait on the limiter here
+	r.OnBeforeRequest(c.waitForRetry)
+
+	if c.usesOAuth2() {
+		// Bearer token is attached (and refreshed) per attempt
+		r.OnBeforeRequest(c.authorize)
+	} else {
+		// Legacy mode: credentials go in the query string
+		r.SetQueryParam("client_id", cfg.ClientID)
+		r.SetQueryParam("client_secret", cfg.ClientSecret)
 	}
 
-	r.SetRetryCount(3)
-	r.SetRetryWaitTime(500 * time.Millisecond)
-	r.SetRetryMaxWaitTime(2000 * time.Millisecond)
+	// Let adaptive limiters follow the server's rate-limit headers
+	r.OnAfterResponse(c.observeRateLimit)
+
+	// Config.Debug logs every attempt, redacted, in place of resty's raw dump
+	r.OnAfterResponse(c.logAttempt)
 
 	// Error hook to parse API errors
 	r.OnAfterResponse(func(c *resty.Client, resp *resty.Response) error {
 		if resp.IsError() {
-			apiErr := &APIError{
-				StatusCode: resp.StatusCode(),
-				RequestID:  resp.Header().Get("X-Request-Id"),
-				// Message:    resp.Request.URL,
-			}
-			// Attempt to unmarshal body into error
-			// fmt.Println(string(resp.Body()))
-			_ = json.Unmarshal(resp.Body(), apiErr)
-
-			// If message is empty, use status text
-			if apiErr.Message == "" {
-				apiErr.Message = http.StatusText(resp.StatusCode())
-			}
-			return apiErr
+			return newAPIError(resp.StatusCode(), resp.Header(), resp.Body())
+		}
+		return nil
+	})
+
+	// Hand the typed body and result of R() requests to the middleware chain
+	r.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		if req.Context().Value(inChainKey{}) == nil {
+			req.SetContext(context.WithValue(req.Context(), typedCallKey{}, &typedCall{body: req.Body, result: req.Result}))
 		}
 		return nil
 	})
 
+	// An operation timeout on an R() request is released once the call is over: after a
+	// failure, or once the body has been read
+	r.OnSuccess(releaseTimeoutAfterRead)
+	r.OnError(func(req *resty.Request, _ error) { releaseTimeout(req.Context()) })
+	r.OnInvalid(func(req *resty.Request, _ error) { releaseTimeout(req.Context()) })
+
+	c.configureRetries()
+	// Wrap whatever transport the options left, so R() requests go through the middleware
+	next := r.GetClient().Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r.SetTransport(&chainTransport{c: c, next: next})
+
 	return c
 }
 
@@ -76,7 +119,7 @@ func (c *Client) Verify(ctx context.Context) error {
 	// But simply checking if we get a 200 OK from an endpoint is enough.
 
 	// Assuming /jobs is a valid endpoint that requires auth.
-	resp, err := c.R(ctx).
+	resp, err := c.R(WithOperation(ctx, "client.Verify")).
 		SetQueryParam("per_page", "1").
 		Get("/v2/jobs")
 
@@ -91,33 +134,60 @@ func (c *Client) Verify(ctx context.Context) error {
 	return nil
 }
 
-// R creates a new request with the context and limiter wait
+// R creates a new request with the context and limiter wait.
+// The operation timeout, if any, starts here.
 func (c *Client) R(ctx context.Context) *resty.Request {
-	_ = c.limiter.Wait(ctx)
+	if ctx.Value(inChainKey{}) == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = c.withOperationTimeout(ctx)
+		ctx = context.WithValue(ctx, timeoutCancelKey{}, cancel)
+	}
+	_ = c.waitLimiter(ctx)
 	return c.http.R().SetContext(ctx)
 }
 
+// Budget returns the limiter's current budget, if the limiter reports one (rate.Adaptive, the default, does)
+func (c *Client) Budget() (rate.Budget, bool) {
+	if b, ok := c.limiter.(interface{ Budget() rate.Budget }); ok {
+		return b.Budget(), true
+	}
+	return rate.Budget{}, false
+}
+
+// waitForRetry is a resty request middleware that makes retries wait on the limiter like first attempts
+func (c *Client) waitForRetry(_ *resty.Client, req *resty.Request) error {
+	if req.Attempt <= 1 {
+		return nil
+	}
+	return c.waitLimiter(req.Context())
+}
+
+// observeRateLimit is a resty response middleware that reports every response to a limiter implementing rate.Observer
+func (c *Client) observeRateLimit(_ *resty.Client, resp *resty.Response) error {
+	if o, ok := c.limiter.(rate.Observer); ok {
+		o.Observe(resp.Header(), resp.StatusCode())
+	}
+	return nil
+}
+
 // REST methods helpers
 
 func (c *Client) Get(ctx context.Context, path string, result interface{}) error {
-	_, err := c.R(ctx).SetResult(result).Get(path)
-	return err
+	return c.do(ctx, http.MethodGet, path, nil, nil, result)
 }
 
 func (c *Client) Post(ctx context.Context, path string, body interface{}, result interface{}) error {
 	if err := c.validatePayloadSize(body); err != nil {
 		return err
 	}
-	_, err := c.R(ctx).SetBody(body).SetResult(result).Post(path)
-	return err
+	return c.do(ctx, http.MethodPost, path, nil, body, result)
 }
 
 func (c *Client) Put(ctx context.Context, path string, body interface{}, result interface{}) error {
 	if err := c.validatePayloadSize(body); err != nil {
 		return err
 	}
-	_, err := c.R(ctx).SetBody(body).SetResult(result).Put(path)
-	return err
+	return c.do(ctx, http.MethodPut, path, nil, body, result)
 }
 
 func (c *Client) validatePayloadSize(body interface{}) error {
@@ -136,6 +206,14 @@ func (c *Client) validatePayloadSize(body interface{}) error {
 }
 
 func (c *Client) Delete(ctx context.Context, path string) error {
-	_, err := c.R(ctx).Delete(path)
-	return err
+	return c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+// GetWithQuery performs a GET with query parameters encoded from the `url` tags of query
+func (c *Client) GetWithQuery(ctx context.Context, path string, query interface{}, result interface{}) error {
+	params, err := EncodeQuery(query)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodGet, path, params, nil, result)
 }
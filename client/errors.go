@@ -1,6 +1,7 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
@@ -17,6 +18,19 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("versafleet-sdk: status=%d message=%s request_id=%s", e.StatusCode, e.Message, e.RequestID)
 }
 
+// newAPIError builds the error for an error response, taking the message and details from the body if it has them
+func newAPIError(statusCode int, header http.Header, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		RequestID:  header.Get("X-Request-Id"),
+	}
+	_ = json.Unmarshal(body, apiErr)
+	if apiErr.Message == "" || apiErr.Message == nil {
+		apiErr.Message = http.StatusText(statusCode)
+	}
+	return apiErr
+}
+
 // IsNotFound checks if the error is a 404
 func (e *APIError) IsNotFound() bool {
 	return e.StatusCode == http.StatusNotFound
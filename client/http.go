@@ -0,0 +1,211 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultTimeout limits each attempt unless WithTimeout, an injected http.Client or the config says otherwise
+const DefaultTimeout = time.Minute
+
+// httpSettings are the transport-level settings from the config and options, applied by newResty
+type httpSettings struct {
+	httpClient *http.Client
+	transport  http.RoundTripper
+	proxyURL   string
+	tlsConfig  *tls.Config
+	timeout    time.Duration // From WithTimeout
+	cfgTimeout time.Duration // From the config, below an injected http.Client's own timeout
+	userAgent  string
+	header     http.Header
+}
+
+// setConfigErr keeps the first invalid setting, which New logs and every request returns
+func (c *Client) setConfigErr(err error) {
+	if c.configErr == nil {
+		c.configErr = err
+	}
+}
+
+// applyConfig turns the config's HTTP settings into the same state the options set
+func (c *Client) applyConfig() {
+	cfg := c.config
+	c.settings.cfgTimeout = cfg.Timeout
+	c.settings.userAgent = cfg.UserAgent
+	c.settings.proxyURL = cfg.ProxyURL
+	c.settings.header = make(http.Header)
+	c.opTimeouts = make(map[string]time.Duration)
+
+	for _, entry := range cfg.Headers {
+		name, value, ok := strings.Cut(entry, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			c.setConfigErr(fmt.Errorf("versafleet-sdk: header %q is not in the form \"Name: value\"", entry))
+			continue
+		}
+		c.settings.header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	for _, entry := range cfg.OperationTimeouts {
+		op, value, ok := strings.Cut(entry, "=")
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || err != nil || d <= 0 {
+			c.setConfigErr(fmt.Errorf("versafleet-sdk: operation timeout %q is not in the form \"operation=duration\"", entry))
+			continue
+		}
+		c.opTimeouts[strings.TrimSpace(op)] = d
+	}
+
+	if cfg.TLSCAFile != "" || cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" || cfg.TLSInsecureSkipVerify {
+		tlsConfig, err := configTLS(cfg.TLSCAFile, cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			c.setConfigErr(err)
+		} else {
+			tlsConfig.InsecureSkipVerify = cfg.TLSInsecureSkipVerify
+			c.settings.tlsConfig = tlsConfig
+		}
+	}
+}
+
+// configTLS builds a TLS config trusting caFile on top of the system roots and presenting the
+// certFile/keyFile pair, for whichever of them are set
+func configTLS(caFile, certFile, keyFile string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("versafleet-sdk: failed to read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("versafleet-sdk: no certificates found in CA file %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("versafleet-sdk: failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// newResty builds the resty client from the settings. It never changes an injected
+// http.Client or transport; proxy and TLS settings go on a clone.
+func (c *Client) newResty() *resty.Client {
+	s := c.settings
+	var r *resty.Client
+	if s.httpClient != nil {
+		hc := *s.httpClient
+		r = resty.NewWithClient(&hc)
+	} else {
+		r = resty.New()
+	}
+	r.SetBaseURL(c.config.BaseURL)
+
+	if s.transport != nil {
+		r.SetTransport(s.transport)
+	}
+	if s.proxyURL != "" || s.tlsConfig != nil {
+		if err := c.configureTransport(r); err != nil {
+			c.setConfigErr(err)
+		}
+	}
+
+	// config.Load always sets a timeout, so an injected client's timeout only gives way to WithTimeout
+	switch {
+	case s.timeout > 0:
+		r.SetTimeout(s.timeout)
+	case r.GetClient().Timeout > 0:
+	case s.cfgTimeout > 0:
+		r.SetTimeout(s.cfgTimeout)
+	default:
+		r.SetTimeout(DefaultTimeout)
+	}
+
+	if s.userAgent != "" {
+		r.SetHeader("User-Agent", s.userAgent)
+	}
+	for name, values := range s.header {
+		r.Header[name] = append([]string(nil), values...)
+	}
+	return r
+}
+
+// configureTransport applies the proxy and TLS settings to a clone of the current transport
+func (c *Client) configureTransport(r *resty.Client) error {
+	t, err := r.Transport()
+	if err != nil {
+		return fmt.Errorf("versafleet-sdk: proxy and TLS settings need an *http.Transport, not %T", r.GetClient().Transport)
+	}
+	t = t.Clone()
+	if c.settings.proxyURL != "" {
+		proxy, err := url.Parse(c.settings.proxyURL)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return fmt.Errorf("versafleet-sdk: invalid proxy URL %q", c.settings.proxyURL)
+		}
+		t.Proxy = http.ProxyURL(proxy)
+	}
+	if c.settings.tlsConfig != nil {
+		t.TLSClientConfig = c.settings.tlsConfig.Clone()
+	}
+	r.SetTransport(t)
+	return nil
+}
+
+// timeoutCancelKey carries the cancel func of an R() request's operation timeout
+type timeoutCancelKey struct{}
+
+// withOperationTimeout applies the timeout set for the context's operation, if there is one
+func (c *Client) withOperationTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d, ok := c.opTimeouts[OperationFrom(ctx)]; ok {
+		return context.WithTimeout(ctx, d)
+	}
+	return ctx, func() {}
+}
+
+// releaseTimeout cancels an R() request's operation timeout early
+func releaseTimeout(ctx context.Context) {
+	if cancel, ok := ctx.Value(timeoutCancelKey{}).(context.CancelFunc); ok {
+		cancel()
+	}
+}
+
+// releaseTimeoutAfterRead is the resty success hook releasing an R() request's operation
+// timeout. resty has read the body by now, unless the request set SetDoNotParseResponse,
+// in which case the timeout is released when the caller closes RawBody.
+func releaseTimeoutAfterRead(_ *resty.Client, resp *resty.Response) {
+	ctx := resp.Request.Context()
+	raw := resp.RawResponse
+	if resp.Body() != nil || raw == nil || raw.Body == nil {
+		releaseTimeout(ctx)
+		return
+	}
+	raw.Body = &releasingBody{ReadCloser: raw.Body, ctx: ctx}
+}
+
+// releasingBody releases the operation timeout when the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	ctx context.Context
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	releaseTimeout(b.ctx)
+	return err
+}
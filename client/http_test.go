@@ -0,0 +1,333 @@
+package client_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/config"
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+	"github.com/Willias7788/go-versafleet-sdk/versafleettest"
+)
+
+// recorder is a server answering {} to everything and keeping the requests it got
+type recorder struct {
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec.mu.Lock()
+	rec.requests = append(rec.requests, r)
+	rec.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{}`))
+}
+
+func (rec *recorder) count() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return len(rec.requests)
+}
+
+func (rec *recorder) last() *http.Request {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.requests[len(rec.requests)-1]
+}
+
+// queryConfig is a config for baseURL without a token endpoint
+func queryConfig(baseURL string) *config.Config {
+	return &config.Config{BaseURL: baseURL, ClientID: "id", ClientSecret: "secret", AuthMode: config.AuthModeQuery}
+}
+
+func get(c *client.Client) error {
+	return c.Get(context.Background(), "/customers", &map[string]interface{}{})
+}
+
+func TestTimeoutPrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfgTimeout time.Duration
+		opts       []client.Option
+	}{
+		{"injected client over config", time.Minute, []client.Option{client.WithHTTPClient(&http.Client{Timeout: 200 * time.Millisecond})}},
+		{"WithTimeout over injected client", time.Minute, []client.Option{
+			client.WithHTTPClient(&http.Client{Timeout: time.Minute}),
+			client.WithTimeout(200 * time.Millisecond),
+		}},
+		{"config without injected client", 200 * time.Millisecond, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := versafleettest.NewServer()
+			defer srv.Close()
+			srv.SetLatency(time.Second)
+
+			cfg := srv.Config()
+			cfg.Timeout = tt.cfgTimeout
+			opts := append([]client.Option{
+				client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100})),
+				client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}),
+			}, tt.opts...)
+
+			start := time.Now()
+			err := client.New(cfg, opts...).Get(context.Background(), "/customers", &map[string]interface{}{})
+			elapsed := time.Since(start)
+			if err == nil {
+				t.Fatal("Get succeeded against a server slower than the timeout")
+			}
+			if elapsed > 800*time.Millisecond {
+				t.Errorf("gave up after %v, want about 200ms", elapsed)
+			}
+		})
+	}
+}
+
+func TestProxy(t *testing.T) {
+	proxy := &recorder{}
+	proxySrv := httptest.NewServer(proxy)
+	defer proxySrv.Close()
+	target := &recorder{}
+	targetSrv := httptest.NewServer(target)
+	defer targetSrv.Close()
+
+	tests := []struct {
+		name      string
+		cfgProxy  string
+		opts      []client.Option
+		wantProxy bool // Otherwise the client must fail without a direct connection
+	}{
+		{"WithProxy", "", []client.Option{client.WithProxy(proxySrv.URL)}, true},
+		{"config", proxySrv.URL, nil, true},
+		{"WithProxy over config", "http://unused.invalid:3128", []client.Option{client.WithProxy(proxySrv.URL)}, true},
+		{"invalid URL", "proxy.corp", nil, false},
+		{"not an *http.Transport", proxySrv.URL, []client.Option{client.WithTransport(roundTripper(http.DefaultTransport.RoundTrip))}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, beforeTarget := proxy.count(), target.count()
+			cfg := queryConfig(targetSrv.URL)
+			cfg.ProxyURL = tt.cfgProxy
+			err := get(client.New(cfg, append([]client.Option{client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100}))}, tt.opts...)...))
+
+			if target.count() != beforeTarget {
+				t.Error("request went straight to the API")
+			}
+			if !tt.wantProxy {
+				if err == nil || proxy.count() != before {
+					t.Errorf("Get = %v with %d proxied requests, want an error and none", err, proxy.count()-before)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			// A proxy gets the absolute URL of the API
+			if got := proxy.last().URL.String(); got != targetSrv.URL+"/customers?client_id=id&client_secret=secret" {
+				t.Errorf("proxy got %s", got)
+			}
+		})
+	}
+}
+
+// roundTripper adapts a function to http.RoundTripper
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// writePEM writes blocks of type typ to a file in dir and returns its path
+func writePEM(t *testing.T, dir, name, typ string, blocks ...[]byte) string {
+	t.Helper()
+	var out []byte
+	for _, b := range blocks {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b})...)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, out, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTLS(t *testing.T) {
+	target := &recorder{}
+	srv := httptest.NewUnstartedServer(target)
+	// Ask for a client certificate without verifying it, so the test can check one was sent
+	srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0) // The untrusted case fails the handshake
+	srv.StartTLS()
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cert := srv.Certificate()
+	caFile := writePEM(t, dir, "ca.pem", "CERTIFICATE", cert.Raw)
+	key, err := x509.MarshalPKCS8PrivateKey(srv.TLS.Certificates[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := writePEM(t, dir, "cert.pem", "CERTIFICATE", srv.TLS.Certificates[0].Certificate...)
+	keyFile := writePEM(t, dir, "key.pem", "PRIVATE KEY", key)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	tests := []struct {
+		name     string
+		cfg      func(*config.Config)
+		opts     []client.Option
+		wantErr  bool
+		wantCert bool // A client certificate is presented
+	}{
+		{"untrusted server", nil, nil, true, false},
+		{"CA file", func(c *config.Config) { c.TLSCAFile = caFile }, nil, false, false},
+		{"client certificate", func(c *config.Config) { c.TLSCAFile, c.TLSCertFile, c.TLSKeyFile = caFile, certFile, keyFile }, nil, false, true},
+		{"skip verify", func(c *config.Config) { c.TLSInsecureSkipVerify = true }, nil, false, false},
+		{"unreadable CA file", func(c *config.Config) { c.TLSCAFile = filepath.Join(dir, "missing.pem") }, nil, true, false},
+		{"CA file without certificates", func(c *config.Config) { c.TLSCAFile = keyFile }, nil, true, false},
+		{"WithTLSConfig", nil, []client.Option{client.WithTLSConfig(&tls.Config{RootCAs: pool})}, false, false},
+		// The option replaces the config's TLS settings entirely
+		{"WithTLSConfig over config", func(c *config.Config) { c.TLSCAFile, c.TLSCertFile, c.TLSKeyFile = caFile, certFile, keyFile },
+			[]client.Option{client.WithTLSConfig(&tls.Config{RootCAs: pool})}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := target.count()
+			cfg := queryConfig(srv.URL)
+			if tt.cfg != nil {
+				tt.cfg(cfg)
+			}
+			opts := append([]client.Option{
+				client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100})),
+				client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}),
+			}, tt.opts...)
+			err := get(client.New(cfg, opts...))
+			if tt.wantErr {
+				if err == nil || target.count() != before {
+					t.Errorf("Get = %v, want an error before reaching the server", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if sent := len(target.last().TLS.PeerCertificates) > 0; sent != tt.wantCert {
+				t.Errorf("client certificate sent = %v, want %v", sent, tt.wantCert)
+			}
+		})
+	}
+}
+
+func TestDefaultHeaders(t *testing.T) {
+	target := &recorder{}
+	srv := httptest.NewServer(target)
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		headers []string
+		ua      string
+		opts    []client.Option
+		want    http.Header // nil when the config is invalid
+	}{
+		{"config", []string{"X-Tenant: acme", "X-Trace:1", "X-Trace: 2"}, "fleet-sync/1.0", nil,
+			http.Header{"X-Tenant": {"acme"}, "X-Trace": {"1", "2"}, "User-Agent": {"fleet-sync/1.0"}}},
+		{"options over config", []string{"X-Tenant: acme"}, "fleet-sync/1.0",
+			[]client.Option{client.WithHeader("X-Tenant", "globex"), client.WithHeader("X-Region", "sg"), client.WithUserAgent("importer/2.0")},
+			http.Header{"X-Tenant": {"globex"}, "X-Region": {"sg"}, "User-Agent": {"importer/2.0"}}},
+		{"malformed header", []string{"X-Tenant acme"}, "", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := target.count()
+			cfg := queryConfig(srv.URL)
+			cfg.Headers, cfg.UserAgent = tt.headers, tt.ua
+			c := client.New(cfg, append([]client.Option{client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100}))}, tt.opts...)...)
+
+			// Both the helpers and R() send the defaults
+			for _, send := range []func() error{
+				func() error { return get(c) },
+				func() error { _, err := c.R(context.Background()).Get("/customers"); return err },
+			} {
+				err := send()
+				if tt.want == nil {
+					if err == nil || target.count() != before {
+						t.Errorf("request = %v, want an error before reaching the server", err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("request: %v", err)
+				}
+				got := target.last().Header
+				for name, want := range tt.want {
+					if !slices.Equal(got.Values(name), want) {
+						t.Errorf("%s = %q, want %q", name, got.Values(name), want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestOperationTimeout(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	srv.SetLatency(300 * time.Millisecond)
+	c := client.New(srv.Config(),
+		client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100})),
+		client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}),
+		client.WithOperationTimeout("slow", 50*time.Millisecond),
+		client.WithOperationTimeout("roomy", 5*time.Second),
+	)
+
+	start := time.Now()
+	err := c.Get(client.WithOperation(context.Background(), "slow"), "/customers", &map[string]interface{}{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("slow operation = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("slow operation gave up after %v, want about 50ms", elapsed)
+	}
+	if err := c.Get(client.WithOperation(context.Background(), "other"), "/customers", &map[string]interface{}{}); err != nil {
+		t.Errorf("operation without a timeout: %v", err)
+	}
+
+	// An R() request's timeout is released as soon as it has finished, not at the deadline
+	resp, err := c.R(client.WithOperation(context.Background(), "roomy")).Get("/customers")
+	if err != nil {
+		t.Fatalf("R(): %v", err)
+	}
+	if err := resp.Request.Context().Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("context after a successful R() = %v, want canceled", err)
+	}
+
+	// Unless the caller reads the body itself, in which case it's released when they close it
+	resp, err = c.R(client.WithOperation(context.Background(), "roomy")).SetDoNotParseResponse(true).Get("/customers")
+	if err != nil {
+		t.Fatalf("R() without parsing: %v", err)
+	}
+	if err := resp.Request.Context().Err(); err != nil {
+		t.Fatalf("context released before the body was read: %v", err)
+	}
+	body := resp.RawBody()
+	if _, err := io.ReadAll(body); err != nil {
+		t.Errorf("reading the body: %v", err)
+	}
+	body.Close()
+	if err := resp.Request.Context().Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("context after closing the body = %v, want canceled", err)
+	}
+}
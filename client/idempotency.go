@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey attaches key to create and update calls made with the returned context.
+// Reuse the same key when retrying a call whose outcome is unknown (e.g. after a timeout).
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// IdempotencyKeyFrom returns the key attached with WithIdempotencyKey, or ""
+func IdempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyCtx{}).(string)
+	return key
+}
+
+// EnsureIdempotencyKey returns ctx with a newly generated key if it doesn't carry one already
+func EnsureIdempotencyKey(ctx context.Context) (context.Context, string) {
+	if key := IdempotencyKeyFrom(ctx); key != "" {
+		return ctx, key
+	}
+	key := NewIdempotencyKey()
+	return WithIdempotencyKey(ctx, key), key
+}
+
+// NewIdempotencyKey returns a random UUID (version 4)
+func NewIdempotencyKey() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// IdempotencyRecord is what the local fallback remembers about a keyed create
+type IdempotencyRecord struct {
+	Key       string
+	ID        int // ID of the created resource; 0 while the outcome is unknown
+	CreatedAt time.Time
+}
+
+// IdempotencyStore remembers recent idempotency keys for the local fallback, which
+// returns the earlier result when a create is repeated with the same key
+type IdempotencyStore interface {
+	// Get returns the record for key, or nil if the key is unknown
+	Get(ctx context.Context, key string) (*IdempotencyRecord, error)
+	Put(ctx context.Context, rec *IdempotencyRecord) error
+	Delete(ctx context.Context, key string) error
+}
+
+// WithIdempotencyStore enables the local idempotency fallback for services that support it (jobs.Service.Create)
+func WithIdempotencyStore(store IdempotencyStore) Option {
+	return func(c *Client) {
+		c.idempotency = store
+	}
+}
+
+// IdempotencyStore returns the store set with WithIdempotencyStore, or nil
+func (c *Client) IdempotencyStore() IdempotencyStore {
+	return c.idempotency
+}
+
+// MemoryIdempotencyStore keeps keys in memory for a limited time
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	records map[string]IdempotencyRecord
+}
+
+// NewMemoryIdempotencyStore creates a store that forgets keys after ttl (24h if ttl <= 0)
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return &MemoryIdempotencyStore{ttl: ttl, records: make(map[string]IdempotencyRecord)}
+}
+
+func (s *MemoryIdempotencyStore) Get(_ context.Context, key string) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	rec, ok := s.records[key]
+	if !ok {
+		return nil, nil
+	}
+	return &rec, nil
+}
+
+func (s *MemoryIdempotencyStore) Put(_ context.Context, rec *IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	s.records[rec.Key] = *rec
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// expire drops records older than the TTL. Callers hold s.mu.
+func (s *MemoryIdempotencyStore) expire() {
+	cutoff := time.Now().Add(-s.ttl)
+	for key, rec := range s.records {
+		if rec.CreatedAt.Before(cutoff) {
+			delete(s.records, key)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Redacted replaces secrets and PII in logs
+const Redacted = "[REDACTED]"
+
+// secretKeys are always redacted: any key containing one of them is treated as a credential
+var secretKeys = []string{"secret", "token", "password", "signature", "authorization", "cookie", "api_key", "apikey"}
+
+// DefaultPIIFields are the personal fields redacted from logs unless WithPIIFields says otherwise.
+// Like secrets, a key matches if it contains the field, so "contact_number" also covers "sender_contact_number".
+var DefaultPIIFields = []string{"contact_number", "phone", "mobile", "email", "nric"}
+
+// WithLogger sends the SDK's logs to l. Secrets and PII are redacted before they reach l's handler.
+//
+// Requests are logged at Debug, retries and token refreshes at Info. Without a logger nothing is
+// logged, unless Config.Debug is set, in which case Debug logs including bodies go to stderr.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithPIIFields replaces DefaultPIIFields. Call it with no fields to log personal data unredacted.
+func WithPIIFields(fields ...string) Option {
+	return func(c *Client) {
+		c.piiFields = append([]string{}, fields...)
+	}
+}
+
+// Logger returns the client's logger, with redaction applied. It is never nil.
+func (c *Client) Logger() *slog.Logger {
+	return c.log
+}
+
+// configureLogging builds the redacting logger once the options have been applied
+func (c *Client) configureLogging() {
+	base := c.logger
+	if base == nil {
+		if c.config.Debug {
+			base = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		} else {
+			base = slog.New(slog.DiscardHandler)
+		}
+	}
+	c.log = slog.New(NewRedactingHandler(base.Handler(), c.piiFields...))
+}
+
+// logAttempt is a resty response middleware that logs each attempt's raw request and response in debug mode
+func (c *Client) logAttempt(_ *resty.Client, resp *resty.Response) error {
+	if !c.config.Debug {
+		return nil
+	}
+	req := resp.Request
+	ctx := req.Context()
+	if !c.log.Enabled(ctx, slog.LevelDebug) {
+		return nil
+	}
+	c.log.DebugContext(ctx, "versafleet-sdk: attempt",
+		slog.String("operation", OperationFrom(ctx)),
+		slog.String("method", req.Method),
+		slog.Any("url", req.RawRequest.URL),
+		slog.Any("request_header", req.RawRequest.Header),
+		slog.Any("request_body", requestBody(req)),
+		slog.Int("attempt", req.Attempt),
+		slog.Int("status", resp.StatusCode()),
+		slog.Any("response_header", resp.Header()),
+		slog.Any("response_body", resp.Body()),
+	)
+	return nil
+}
+
+// requestBody is the request body as logged: raw bytes as they are, anything else as JSON
+func requestBody(req *resty.Request) []byte {
+	if len(req.FormData) > 0 {
+		return []byte(req.FormData.Encode())
+	}
+	switch b := req.Body.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return b
+	case string:
+		return []byte(b)
+	default:
+		data, _ := json.Marshal(b)
+		return data
+	}
+}
+
+// restyLogger routes resty's own warnings (one per retry) to the SDK logger at Debug,
+// since the SDK logs retries itself and returns the final error to the caller
+type restyLogger struct {
+	c *Client
+}
+
+func (l restyLogger) Errorf(format string, v ...interface{}) { l.log(format, v...) }
+func (l restyLogger) Warnf(format string, v ...interface{})  { l.log(format, v...) }
+func (l restyLogger) Debugf(format string, v ...interface{}) { l.log(format, v...) }
+
+func (l restyLogger) log(format string, v ...interface{}) {
+	if l.c.log == nil {
+		return
+	}
+	l.c.log.Debug("resty: " + strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+// redactingHandler redacts secrets and PII from records before passing them on
+type redactingHandler struct {
+	next slog.Handler
+	r    *redactor
+}
+
+// NewRedactingHandler wraps h so that attributes named like a credential, token or signature
+// are redacted, as are the given PII fields (DefaultPIIFields if piiFields is nil). The same keys
+// are redacted inside strings, errors, []byte bodies, url.Values, http.Header and *url.URL, and
+// bearer tokens are redacted wherever they appear.
+func NewRedactingHandler(h slog.Handler, piiFields ...string) slog.Handler {
+	if piiFields == nil {
+		piiFields = DefaultPIIFields
+	}
+	return &redactingHandler{next: h, r: newRedactor(piiFields)}
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, rec slog.Record) error {
+	out := slog.NewRecord(rec.Time, rec.Level, h.r.text(rec.Message), rec.PC)
+	rec.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.r.attr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.r.attr(a)
+	}
+	return &redactingHandler{next: h.next.WithAttrs(redacted), r: h.r}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name), r: h.r}
+}
+
+type redactor struct {
+	keys   []string
+	pairs  *regexp.Regexp // key=value, as in query strings and form bodies
+	json   *regexp.Regexp // "key": value
+	bearer *regexp.Regexp
+}
+
+func newRedactor(piiFields []string) *redactor {
+	keys := append(append([]string(nil), secretKeys...), piiFields...)
+	quoted := make([]string, len(keys))
+	for i, k := range keys {
+		keys[i] = strings.ToLower(k)
+		quoted[i] = regexp.QuoteMeta(keys[i])
+	}
+	key := `[\w.\-]*(?:` + strings.Join(quoted, "|") + `)[\w.\-]*`
+	return &redactor{
+		keys:   keys,
+		pairs:  regexp.MustCompile(`(?i)\b(` + key + `)=[^&\s"']*`),
+		json:   regexp.MustCompile(`(?i)"(` + key + `)"(\s*):(\s*)(?:"(?:[^"\\]|\\.)*"|[\-+\d.eE]+)`),
+		bearer: regexp.MustCompile(`(?i)\b(bearer\s+)[\w\-.~+/=]+`),
+	}
+}
+
+// sensitive reports whether values under key should be redacted
+func (r *redactor) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range r.keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *redactor) text(s string) string {
+	s = r.pairs.ReplaceAllString(s, "${1}="+Redacted)
+	s = r.json.ReplaceAllString(s, `"${1}"${2}:${3}"`+Redacted+`"`)
+	return r.bearer.ReplaceAllString(s, "${1}"+Redacted)
+}
+
+func (r *redactor) attr(a slog.Attr) slog.Attr {
+	if r.sensitive(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.text(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			attrs[i] = r.attr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+	case slog.KindAny:
+		return slog.Any(a.Key, r.any(v.Any()))
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+func (r *redactor) any(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []byte:
+		return r.text(string(v))
+	case error:
+		return r.text(v.Error())
+	case url.Values:
+		return r.text(v.Encode())
+	case *url.URL:
+		if v == nil {
+			return v
+		}
+		u := *v
+		u.User = nil
+		return r.text(u.String())
+	case http.Header:
+		out := make(http.Header, len(v))
+		for k, vals := range v {
+			if r.sensitive(k) {
+				out[k] = []string{Redacted}
+				continue
+			}
+			out[k] = make([]string, len(vals))
+			for i, s := range vals {
+				out[k][i] = r.text(s)
+			}
+		}
+		return out
+	case fmt.Stringer:
+		return r.text(v.String())
+	}
+	return v
+}
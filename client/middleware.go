@@ -0,0 +1,348 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Call is one API call as seen by middleware
+type Call struct {
+	// Operation names the logical call, e.g. "tasks.List" or "jobs.Create".
+	// Calls not named by a service use "METHOD /path".
+	Operation string
+
+	Method string
+	Path   string // Relative to the base URL, e.g. "/v2/jobs" even when the base URL ends in "/api"
+	Query  url.Values
+	Header http.Header
+
+	// Body is the typed request body (e.g. *model.JobParams), nil for none.
+	// Result is where the response is decoded to (e.g. *model.JobResponse), nil if ignored.
+	Body   interface{}
+	Result interface{}
+
+	// Page is the page number when the call fetches a page for an Iterator, otherwise 0
+	Page int
+
+	ctx context.Context
+}
+
+// Context returns the call's context
+func (c *Call) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// WithContext returns a shallow copy of the call with ctx, for middleware passing values down the chain
+func (c *Call) WithContext(ctx context.Context) *Call {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+// Response is the API's answer to a Call
+type Response struct {
+	StatusCode int // 0 if no response arrived
+	Header     http.Header
+	Body       []byte
+
+	// Set by the client for calls through the helper methods
+	Attempts    int           // Attempts made, including retries
+	LimiterWait time.Duration // Time spent waiting on the rate limiter, over all attempts
+
+	sent bool // Came from the API rather than a short-circuiting middleware
+}
+
+// RoundTripper executes a Call. A failed call returns an error (an *APIError for error statuses)
+// together with the response.
+type RoundTripper interface {
+	RoundTrip(call *Call) (*Response, error)
+}
+
+// RoundTripperFunc adapts a function to RoundTripper
+type RoundTripperFunc func(call *Call) (*Response, error)
+
+func (f RoundTripperFunc) RoundTrip(call *Call) (*Response, error) {
+	return f(call)
+}
+
+// Middleware wraps a RoundTripper. It can change the call before passing it on, inspect or
+// replace the response and error, or answer without calling next at all. A response that
+// doesn't come from next is decoded into call.Result (or into an *APIError for status >= 400)
+// unless Body is empty.
+type Middleware func(next RoundTripper) RoundTripper
+
+// WithMiddleware adds middleware around every call made through Get, Post, Put, Delete,
+// GetWithQuery and R. The first middleware is the outermost.
+//
+// Calls through the helper methods pass through the chain once, around retries. Requests built
+// with R are executed by the caller, so they pass through on every attempt, and only see an
+// error for network failures; error statuses are in the Response.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+type operationKey struct{}
+
+// WithOperation names the calls made with the returned context, for middleware, logs and traces
+func WithOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationKey{}, name)
+}
+
+// OperationFrom returns the name set with WithOperation, or ""
+func OperationFrom(ctx context.Context) string {
+	name, _ := ctx.Value(operationKey{}).(string)
+	return name
+}
+
+// chain wraps terminal in the client's middleware
+func (c *Client) chain(terminal RoundTripper) RoundTripper {
+	rt := terminal
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		rt = c.middleware[i](rt)
+	}
+	return rt
+}
+
+// inChainKey marks resty requests made by the chain's terminal, so chainTransport lets them through
+type inChainKey struct{}
+
+// do runs a call through the middleware chain
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, result interface{}) error {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	call := &Call{
+		Operation: OperationFrom(ctx),
+		Method:    method,
+		Path:      path,
+		Query:     query,
+		Header:    make(http.Header),
+		Body:      body,
+		Result:    result,
+		ctx:       ctx,
+	}
+	if call.Operation == "" {
+		call.Operation = method + " " + path
+	}
+	if call.Query == nil {
+		call.Query = make(url.Values)
+	}
+	if key := IdempotencyKeyFrom(ctx); key != "" {
+		call.Header.Set(IdempotencyKeyHeader, key)
+	}
+	call.Page, _ = ctx.Value(pageKey{}).(int)
+
+	start := time.Now()
+	resp, err := c.chain(RoundTripperFunc(c.send)).RoundTrip(call)
+	c.logCall(call, resp, err, time.Since(start))
+	if err != nil {
+		return err
+	}
+	if resp != nil && !resp.sent {
+		return decodeResponse(call, resp)
+	}
+	return nil
+}
+
+// logCall logs a finished call at Debug
+func (c *Client) logCall(call *Call, resp *Response, err error, elapsed time.Duration) {
+	ctx := call.Context()
+	if !c.log.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("operation", call.Operation),
+		slog.String("method", call.Method),
+		slog.String("path", call.Path),
+		slog.Duration("duration", elapsed),
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode), slog.Int("attempts", resp.Attempts))
+	}
+	if call.Page > 0 {
+		attrs = append(attrs, slog.Int("page", call.Page))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	c.log.LogAttrs(ctx, slog.LevelDebug, "versafleet-sdk: call", attrs...)
+}
+
+// send is the end of the chain: it makes the request through resty, with retries
+func (c *Client) send(call *Call) (*Response, error) {
+	stats := &callStats{}
+	ctx := context.WithValue(call.Context(), inChainKey{}, true)
+	ctx = context.WithValue(ctx, callStatsKey{}, stats)
+	req := c.R(ctx).
+		SetHeaderMultiValues(call.Header).
+		SetQueryParamsFromValues(call.Query)
+	if call.Body != nil {
+		req.SetBody(call.Body)
+	}
+	if call.Result != nil {
+		req.SetResult(call.Result)
+	}
+
+	resp, err := req.Execute(call.Method, call.Path)
+	out := &Response{Attempts: max(req.Attempt, 1), LimiterWait: stats.limiterWait(), sent: true}
+	if resp != nil && resp.RawResponse != nil {
+		out.StatusCode = resp.StatusCode()
+		out.Header = resp.Header()
+		out.Body = resp.Body()
+	}
+	return out, err
+}
+
+type pageKey struct{}
+
+// withPage marks calls made with ctx as fetching the given page for an Iterator
+func withPage(ctx context.Context, page int) context.Context {
+	return context.WithValue(ctx, pageKey{}, page)
+}
+
+type callStatsKey struct{}
+
+// callStats collects what happens inside resty during one call
+type callStats struct {
+	mu   sync.Mutex
+	wait time.Duration
+}
+
+func (s *callStats) addLimiterWait(d time.Duration) {
+	s.mu.Lock()
+	s.wait += d
+	s.mu.Unlock()
+}
+
+func (s *callStats) limiterWait() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.wait
+}
+
+// waitLimiter waits on the rate limiter, adding the time spent to the call's stats
+func (c *Client) waitLimiter(ctx context.Context) error {
+	start := time.Now()
+	err := c.limiter.Wait(ctx)
+	if stats, ok := ctx.Value(callStatsKey{}).(*callStats); ok {
+		stats.addLimiterWait(time.Since(start))
+	}
+	return err
+}
+
+// decodeResponse handles a response made up by middleware
+func decodeResponse(call *Call, resp *Response) error {
+	if resp.StatusCode >= 400 {
+		return newAPIError(resp.StatusCode, resp.Header, resp.Body)
+	}
+	if call.Result == nil || len(resp.Body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Body, call.Result)
+}
+
+// typedCallKey carries the typed body and result of an R() request to chainTransport
+type typedCallKey struct{}
+
+type typedCall struct {
+	body, result interface{}
+}
+
+// chainTransport runs requests built with R() through the middleware chain, once per attempt.
+// Requests from the helper methods have been through the chain already and pass straight on.
+type chainTransport struct {
+	c    *Client
+	next http.RoundTripper
+}
+
+// basePath is the path of the base URL without its trailing slash, e.g. "/api" for
+// https://host/api/. Call.Path leaves it out, as the helpers' paths do.
+func (c *Client) basePath() string {
+	u, err := url.Parse(c.config.BaseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+func (t *chainTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if len(t.c.middleware) == 0 || ctx.Value(inChainKey{}) != nil || ctx.Value(skipAuthKey{}) != nil {
+		return t.next.RoundTrip(req)
+	}
+
+	base := t.c.basePath()
+	call := &Call{
+		Operation: OperationFrom(ctx),
+		Method:    req.Method,
+		Path:      strings.TrimPrefix(req.URL.Path, base),
+		Query:     req.URL.Query(),
+		Header:    req.Header.Clone(),
+		ctx:       ctx,
+	}
+	if !strings.HasPrefix(req.URL.Path, base+"/") {
+		// Not under the base URL, e.g. an absolute URL passed to R()
+		call.Path, base = req.URL.Path, ""
+	}
+	if call.Operation == "" {
+		call.Operation = req.Method + " " + call.Path
+	}
+	if typed, ok := ctx.Value(typedCallKey{}).(*typedCall); ok {
+		call.Body, call.Result = typed.body, typed.result
+	}
+
+	terminal := RoundTripperFunc(func(call *Call) (*Response, error) {
+		out := req.Clone(call.Context())
+		out.Method = call.Method
+		out.URL.Path = base + call.Path
+		out.URL.RawPath = ""
+		out.URL.RawQuery = call.Query.Encode()
+		out.Header = call.Header
+		resp, err := t.next.RoundTrip(out)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: body, sent: true}, nil
+	})
+
+	resp, err := t.c.chain(terminal).RoundTrip(call)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		resp = &Response{StatusCode: http.StatusNoContent}
+	}
+	header := resp.Header
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        http.StatusText(resp.StatusCode),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(resp.Body)),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/config"
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+)
+
+func TestMiddlewarePathRelativeToBaseURL(t *testing.T) {
+	var mu sync.Mutex
+	var served []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		served = append(served, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var seen []string
+	record := func(next client.RoundTripper) client.RoundTripper {
+		return client.RoundTripperFunc(func(call *client.Call) (*client.Response, error) {
+			seen = append(seen, call.Operation+" -> "+call.Path)
+			return next.RoundTrip(call)
+		})
+	}
+	// Middleware that moves the call to another path, to check the base path is put back
+	redirect := func(next client.RoundTripper) client.RoundTripper {
+		return client.RoundTripperFunc(func(call *client.Call) (*client.Response, error) {
+			if call.Path == "/v2/jobs/old" {
+				call.Path = "/v2/jobs/new"
+			}
+			return next.RoundTrip(call)
+		})
+	}
+
+	cfg := &config.Config{BaseURL: srv.URL + "/api/", ClientID: "id", ClientSecret: "secret", AuthMode: config.AuthModeQuery}
+	c := client.New(cfg,
+		client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100})),
+		client.WithMiddleware(record, redirect),
+	)
+	ctx := context.Background()
+
+	if err := c.Get(ctx, "/v2/jobs", &map[string]interface{}{}); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := c.R(ctx).Get("/v2/jobs"); err != nil {
+		t.Fatalf("R().Get: %v", err)
+	}
+	if _, err := c.R(ctx).Get("/v2/jobs/old"); err != nil {
+		t.Fatalf("R().Get: %v", err)
+	}
+
+	wantSeen := []string{"GET /v2/jobs -> /v2/jobs", "GET /v2/jobs -> /v2/jobs", "GET /v2/jobs/old -> /v2/jobs/old"}
+	if len(seen) != len(wantSeen) {
+		t.Fatalf("middleware saw %q, want %q", seen, wantSeen)
+	}
+	for i := range wantSeen {
+		if seen[i] != wantSeen[i] {
+			t.Errorf("call %d: middleware saw %q, want %q", i, seen[i], wantSeen[i])
+		}
+	}
+
+	wantServed := []string{"/api/v2/jobs", "/api/v2/jobs", "/api/v2/jobs/new"}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(served) != len(wantServed) {
+		t.Fatalf("server got %q, want %q", served, wantServed)
+	}
+	for i := range wantServed {
+		if served[i] != wantServed[i] {
+			t.Errorf("request %d went to %q, want %q", i, served[i], wantServed[i])
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+)
+
+// Option configures a Client in New
+type Option func(*Client)
+
+// WithHTTPClient sends requests with a copy of hc, keeping its transport, cookie jar, redirect
+// policy and timeout. Its timeout, if set, wins over the config's; the other options still
+// apply on top of it.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.settings.httpClient = hc
+	}
+}
+
+// WithTransport sends every request, including token requests, through rt.
+// Use it with cassette.Recorder to record or replay API traffic.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.settings.transport = rt
+	}
+}
+
+// WithProxy sends requests through the proxy at proxyURL (e.g. "http://proxy.corp:3128")
+// instead of the one from HTTP_PROXY/HTTPS_PROXY. It needs an *http.Transport.
+func WithProxy(proxyURL string) Option {
+	return func(c *Client) {
+		c.settings.proxyURL = proxyURL
+	}
+}
+
+// WithTLSConfig replaces the TLS config, e.g. to trust a corporate CA or present a client
+// certificate. It replaces the config's TLS settings entirely and needs an *http.Transport.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *Client) {
+		c.settings.tlsConfig = tlsConfig
+	}
+}
+
+// WithTimeout limits each attempt of a request to d (DefaultTimeout by default)
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.settings.timeout = d
+	}
+}
+
+// WithOperationTimeout limits whole calls of an operation (e.g. "jobs.Create"), including
+// retries and rate-limiter waits, to d. A shorter deadline on the caller's context still wins.
+func WithOperationTimeout(operation string, d time.Duration) Option {
+	return func(c *Client) {
+		c.opTimeouts[operation] = d
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent on every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.settings.userAgent = userAgent
+	}
+}
+
+// WithHeader sends the header on every request, replacing any value for it from the config
+func WithHeader(name, value string) Option {
+	return func(c *Client) {
+		c.settings.header.Set(name, value)
+	}
+}
+
+// WithLimiter replaces the default 100 req/min limiter. Every request, including token
+// requests, waits on it. Use rate.NewDistributed to share one budget between processes.
+func WithLimiter(l rate.Limiter) Option {
+	return func(c *Client) {
+		c.limiter = l
+	}
+}
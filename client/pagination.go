@@ -2,6 +2,9 @@ package client
 
 import (
 	"context"
+	"errors"
+	"iter"
+	"reflect"
 
 	"github.com/Willias7788/go-versafleet-sdk/model"
 )
@@ -18,6 +21,32 @@ type Iterator[T any, O model.Paginatable] struct {
 	err          error
 	fetchFunc    func(context.Context, string, O) ([]T, *model.Meta, error)
 	meta         *model.Meta
+	started      bool
+
+	// runCtx, when set, replaces ctx for fetches; CollectAll uses it to add its own context
+	runCtx context.Context
+
+	// Prefetching state, see WithPrefetch
+	prefetch       int
+	pending        map[int]chan pageResult[T]
+	prefetchCtx    context.Context
+	prefetchCancel context.CancelFunc
+
+	// Checkpointing state, see checkpoint.go
+	skip         int
+	checkpointer Checkpointer
+}
+
+type pageResult[T any] struct {
+	items []T
+	meta  *model.Meta
+	err   error
+}
+
+// Page is a single page of results together with its pagination metadata
+type Page[T any] struct {
+	Items []T
+	Meta  *model.Meta
 }
 
 // NewIterator creates a new iterator.
@@ -56,37 +85,238 @@ func (it *Iterator[T, O]) Next() bool {
 	// if we have items and index is within range, use it.
 	// if index is at end of items, check if we can fetch more.
 
-	if it.currentIndex < len(it.items) {
-		it.currentIndex++
-		return true
+	for {
+		if it.currentIndex < len(it.items) {
+			it.currentIndex++ // 1-based "current" but we access with -1
+			return true
+		}
+		if !it.fetchPage() {
+			return false
+		}
+	}
+}
+
+// fetchPage loads the next page into it.items, leaving currentIndex at 0
+// (or at the resume offset when the iterator was restored from a checkpoint).
+// It returns false when there are no more pages or an error occurred.
+func (it *Iterator[T, O]) fetchPage() bool {
+	if it.err != nil {
+		return false
 	}
 
 	// Need to fetch more?
 	if it.meta != nil && it.listOptions.GetPage() >= it.meta.TotalPages {
+		it.finish()
 		return false // No more pages
 	}
 
+	// Stop between pages if the caller gave up
+	ctx := it.fetchCtx()
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
 	// If it's not the first run, increment page
-	if it.meta != nil {
+	page := it.listOptions.GetPage()
+	if it.started {
 		it.listOptions.SetPage(it.listOptions.GetPage() + 1)
 	}
 
-	items, meta, err := it.fetchFunc(it.ctx, it.path, it.listOptions)
+	var (
+		items []T
+		meta  *model.Meta
+		err   error
+	)
+	if it.prefetch > 0 && it.meta != nil && it.meta.TotalPages > 0 {
+		items, meta, err = it.awaitPage(it.listOptions.GetPage())
+	} else {
+		items, meta, err = it.fetchFunc(withPage(ctx, it.listOptions.GetPage()), it.path, it.listOptions)
+	}
 	if err != nil {
+		// Stay on the page, so an iterator stopped by CollectAll's context can carry on later
+		it.listOptions.SetPage(page)
 		it.err = err
+		it.Close()
 		return false
 	}
+	it.started = true
 
 	if len(items) == 0 {
+		it.finish()
 		return false
 	}
 
 	it.items = items
 	it.meta = meta
-	it.currentIndex = 1 // 1-based "current" but we access with -1
+	it.currentIndex = min(it.skip, len(items))
+	it.skip = 0
+
+	if it.checkpointer != nil {
+		if err := it.saveCheckpoint(); err != nil {
+			it.err = err
+			it.Close()
+			return false
+		}
+	}
 	return true
 }
 
+// WithPrefetch makes the iterator fetch up to n pages ahead concurrently once the first
+// page has reported the total number of pages. Items are still returned in page order,
+// and every fetch still goes through the client's rate limiter. n <= 0 disables prefetching.
+func (it *Iterator[T, O]) WithPrefetch(n int) *Iterator[T, O] {
+	it.prefetch = n
+	return it
+}
+
+// awaitPage returns the given page, scheduling it and the pages after it (up to the
+// prefetch window) if they aren't in flight yet.
+func (it *Iterator[T, O]) awaitPage(page int) ([]T, *model.Meta, error) {
+	if it.pending == nil {
+		it.pending = make(map[int]chan pageResult[T])
+		it.prefetchCtx, it.prefetchCancel = context.WithCancel(it.fetchCtx())
+	}
+
+	last := min(page+it.prefetch-1, it.meta.TotalPages)
+	for p := page; p <= last; p++ {
+		if _, ok := it.pending[p]; ok {
+			continue
+		}
+		ch := make(chan pageResult[T], 1) // buffered so abandoned fetches don't block
+		it.pending[p] = ch
+
+		opts := cloneOptions(it.listOptions)
+		opts.SetPage(p)
+		go func(ctx context.Context) {
+			items, meta, err := it.fetchFunc(ctx, it.path, opts)
+			ch <- pageResult[T]{items: items, meta: meta, err: err}
+		}(withPage(it.prefetchCtx, p))
+	}
+
+	ch := it.pending[page]
+	delete(it.pending, page)
+	select {
+	case res := <-ch:
+		return res.items, res.meta, res.err
+	case <-it.prefetchCtx.Done():
+		return nil, nil, it.prefetchCtx.Err()
+	}
+}
+
+// fetchCtx is the context fetches run under
+func (it *Iterator[T, O]) fetchCtx() context.Context {
+	if it.runCtx != nil {
+		return it.runCtx
+	}
+	return it.ctx
+}
+
+// Close cancels any prefetches still in flight. It is only needed when abandoning a
+// prefetching iterator before it is exhausted; All, Pages and CollectAll call it themselves.
+// The iterator can still be used afterwards, prefetching again from where it stopped.
+func (it *Iterator[T, O]) Close() {
+	if it.prefetchCancel != nil {
+		it.prefetchCancel()
+	}
+	it.pending = nil
+	it.prefetchCtx, it.prefetchCancel = nil, nil
+}
+
+// CollectAll drains the iterator into a slice, stopping after maxItems items
+// (maxItems <= 0 means no limit) or when ctx is done. ctx also cancels fetches in flight,
+// in which case the iterator can carry on later from the page it stopped at.
+func (it *Iterator[T, O]) CollectAll(ctx context.Context, maxItems int) ([]T, error) {
+	runCtx, cancel := context.WithCancel(it.ctx)
+	stop := context.AfterFunc(ctx, cancel)
+	it.runCtx = runCtx
+	defer func() {
+		it.Close()
+		stop()
+		cancel()
+		it.runCtx = nil
+	}()
+
+	var all []T
+	for {
+		if err := ctx.Err(); err != nil {
+			return all, err
+		}
+		if !it.Next() {
+			break
+		}
+		all = append(all, it.Value())
+		if maxItems > 0 && len(all) >= maxItems {
+			break
+		}
+	}
+	// A fetch cut short by ctx sees runCtx's Canceled, whatever ctx's own error is
+	if err := ctx.Err(); err != nil && it.ctx.Err() == nil && errors.Is(it.err, context.Canceled) {
+		it.err = nil
+		return all, err
+	}
+	return all, it.Err()
+}
+
+// cloneOptions makes a shallow copy of pointer options so concurrent
+// fetches can each set their own page.
+func cloneOptions[O model.Paginatable](opts O) O {
+	v := reflect.ValueOf(opts)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return opts
+	}
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+	return c.Interface().(O)
+}
+
+// All returns a range-over-func sequence of every remaining item.
+// A fetch error is yielded once, with a zero item, and ends the sequence.
+// Breaking out of the loop early cancels any prefetches.
+//
+//	for task, err := range iter.All() { ... }
+func (it *Iterator[T, O]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next() {
+			if !yield(it.Value(), nil) {
+				it.Close()
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// Pages returns a range-over-func sequence of the remaining pages.
+// If some items of the current page were already consumed via Next, only the rest of that page is yielded first.
+// Breaking out of the loop early cancels any prefetches.
+func (it *Iterator[T, O]) Pages() iter.Seq2[Page[T], error] {
+	return func(yield func(Page[T], error) bool) {
+		if it.currentIndex < len(it.items) {
+			rest := it.items[it.currentIndex:]
+			it.currentIndex = len(it.items)
+			if !yield(Page[T]{Items: rest, Meta: it.meta}, nil) {
+				it.Close()
+				return
+			}
+		}
+		for it.fetchPage() {
+			rest := it.items[it.currentIndex:]
+			it.currentIndex = len(it.items)
+			if !yield(Page[T]{Items: rest, Meta: it.meta}, nil) {
+				it.Close()
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(Page[T]{}, err)
+		}
+	}
+}
+
 // Value returns the current item.
 func (it *Iterator[T, O]) Value() T {
 	if len(it.items) == 0 || it.currentIndex-1 < 0 || it.currentIndex-1 >= len(it.items) {
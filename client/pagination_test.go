@@ -0,0 +1,149 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+)
+
+// fakePages serves totalPages pages of perPage ints and records which pages were fetched
+// and which fetches were cancelled
+type fakePages struct {
+	totalPages, perPage int
+	block               func(page int) bool // fetches of pages it returns true for wait for cancellation
+
+	mu        sync.Mutex
+	fetched   []int
+	cancelled []int
+}
+
+func (f *fakePages) fetch(ctx context.Context, _ string, opts *model.ListOptions) ([]int, *model.Meta, error) {
+	page := opts.GetPage()
+	f.mu.Lock()
+	f.fetched = append(f.fetched, page)
+	f.mu.Unlock()
+
+	if f.block != nil && f.block(page) {
+		<-ctx.Done()
+		f.mu.Lock()
+		f.cancelled = append(f.cancelled, page)
+		f.mu.Unlock()
+		return nil, nil, ctx.Err()
+	}
+	items := make([]int, f.perPage)
+	for i := range items {
+		items[i] = (page-1)*f.perPage + i
+	}
+	return items, &model.Meta{TotalPages: f.totalPages, CurrentPage: page, PerPage: f.perPage}, nil
+}
+
+func (f *fakePages) cancelledPages() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]int(nil), f.cancelled...)
+}
+
+func (f *fakePages) iterator(ctx context.Context) *client.Iterator[int, *model.ListOptions] {
+	return client.NewIterator(ctx, nil, "/items", &model.ListOptions{PerPage: f.perPage}, f.fetch)
+}
+
+// waitFor polls cond for up to a second
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestAllBreakCancelsPrefetch(t *testing.T) {
+	f := &fakePages{totalPages: 10, perPage: 2, block: func(page int) bool { return page > 2 }}
+	it := f.iterator(context.Background()).WithPrefetch(3)
+
+	for v, err := range it.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v == 2 { // first item of page 2, which starts prefetching pages 3 and 4
+			break
+		}
+	}
+	waitFor(t, func() bool { return len(f.cancelledPages()) == 2 })
+}
+
+func TestPagesBreakCancelsPrefetch(t *testing.T) {
+	f := &fakePages{totalPages: 10, perPage: 2, block: func(page int) bool { return page > 2 }}
+	it := f.iterator(context.Background()).WithPrefetch(3)
+
+	for page, err := range it.Pages() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Meta.CurrentPage == 2 {
+			break
+		}
+	}
+	waitFor(t, func() bool { return len(f.cancelledPages()) == 2 })
+}
+
+func TestCollectAllContextCancelsFetchInFlight(t *testing.T) {
+	f := &fakePages{totalPages: 3, perPage: 2, block: func(page int) bool { return page == 2 }}
+	it := f.iterator(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	items, err := it.CollectAll(ctx, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CollectAll error = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("CollectAll took %v to notice the cancelled context", elapsed)
+	}
+	if len(items) != 2 {
+		t.Errorf("got %d items before the cancel, want 2", len(items))
+	}
+
+	// The iterator carries on from page 2 once it no longer blocks
+	f.block = nil
+	rest, err := it.CollectAll(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("second CollectAll: %v", err)
+	}
+	if len(rest) != 4 || rest[0] != 2 {
+		t.Errorf("second CollectAll = %v, want items 2 to 5", rest)
+	}
+}
+
+func TestIteratorUsableAfterClose(t *testing.T) {
+	f := &fakePages{totalPages: 4, perPage: 2}
+	it := f.iterator(context.Background()).WithPrefetch(2)
+
+	var got []int
+	for v, err := range it.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, v)
+		if v == 3 {
+			break
+		}
+	}
+	for it.Next() {
+		got = append(got, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err after Close: %v", err)
+	}
+	if len(got) != 8 {
+		t.Errorf("got %v, want 8 items", got)
+	}
+}
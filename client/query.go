@@ -0,0 +1,157 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// EncodeQuery turns an options struct into query parameters using its `url` struct tags.
+//
+// Tags follow the `url:"name,omitempty"` convention. Embedded structs are flattened,
+// nil pointers are skipped, slices are sent as repeated keys (or `name[]` with the
+// "brackets" option), bools as true/false and time.Time as RFC 3339 (or YYYY-MM-DD
+// with the "date" option). Fields without a `url` tag or tagged "-" are ignored.
+func EncodeQuery(opts interface{}) (url.Values, error) {
+	values := url.Values{}
+	if opts == nil {
+		return values, nil
+	}
+
+	v := reflect.ValueOf(opts)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return values, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("versafleet-sdk: query options must be a struct, got %s", v.Kind())
+	}
+
+	if err := encodeStruct(values, v); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func encodeStruct(values url.Values, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+
+		tag, hasTag := field.Tag.Lookup("url")
+		if field.Anonymous && !hasTag {
+			for fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					break
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if err := encodeStruct(values, fv); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !hasTag || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name, opts := parseTag(tag)
+		if name == "" {
+			name = field.Name
+		}
+
+		if fv.Kind() == reflect.Ptr {
+			// nil pointer means "not set"; a set pointer is sent even if it
+			// points at a zero value, so Archived=&false reaches the API
+			if fv.IsNil() {
+				continue
+			}
+			fv = reflect.Indirect(fv)
+		} else if opts.has("omitempty") && fv.IsZero() {
+			continue
+		}
+
+		if fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array {
+			key := name
+			if opts.has("brackets") {
+				key += "[]"
+			}
+			for j := 0; j < fv.Len(); j++ {
+				s, err := formatValue(fv.Index(j), opts)
+				if err != nil {
+					return fmt.Errorf("versafleet-sdk: query field %s: %w", field.Name, err)
+				}
+				values.Add(key, s)
+			}
+			continue
+		}
+
+		s, err := formatValue(fv, opts)
+		if err != nil {
+			return fmt.Errorf("versafleet-sdk: query field %s: %w", field.Name, err)
+		}
+		values.Add(name, s)
+	}
+	return nil
+}
+
+func formatValue(v reflect.Value, opts tagOptions) (string, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if opts.has("date") {
+			return t.Format("2006-01-02"), nil
+		}
+		return t.Format(time.RFC3339), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String(), nil
+	}
+	return "", fmt.Errorf("unsupported kind %s", v.Kind())
+}
+
+type tagOptions []string
+
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	return parts[0], tagOptions(parts[1:])
+}
+
+func (o tagOptions) has(opt string) bool {
+	for _, v := range o {
+		if v == opt {
+			return true
+		}
+	}
+	return false
+}
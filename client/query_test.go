@@ -0,0 +1,158 @@
+package client_test
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+)
+
+// status checks that named types are encoded by their underlying kind
+type status string
+
+type queryOptions struct {
+	model.CommonListOptions
+	Name       string    `url:"name,omitempty"`
+	Count      int       `url:"count"`
+	Limit      int       `url:"limit,omitempty"`
+	Ratio      float64   `url:"ratio,omitempty"`
+	Active     bool      `url:"active"`
+	Flag       *bool     `url:"flag,omitempty"`
+	MaybeID    *int      `url:"maybe_id,omitempty"`
+	IDs        []int     `url:"ids,omitempty"`
+	Tags       []string  `url:"tags,omitempty,brackets"`
+	Since      time.Time `url:"since,omitempty"`
+	On         time.Time `url:"on,omitempty,date"`
+	Status     status    `url:"status_name,omitempty"`
+	Skipped    string    `url:"-"`
+	Untagged   string
+	unexported string `url:"unexported"`
+}
+
+func TestEncodeQuery(t *testing.T) {
+	since := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		opts interface{}
+		want url.Values
+	}{
+		{
+			name: "nil",
+			opts: nil,
+			want: url.Values{},
+		},
+		{
+			name: "nil pointer",
+			opts: (*model.JobListOptions)(nil),
+			want: url.Values{},
+		},
+		{
+			name: "zero values",
+			opts: &queryOptions{},
+			// Fields without omitempty are sent even when zero
+			want: url.Values{"count": {"0"}, "active": {"false"}},
+		},
+		{
+			name: "every kind",
+			opts: &queryOptions{
+				Name:       "north",
+				Count:      3,
+				Limit:      10,
+				Ratio:      0.5,
+				Active:     true,
+				Flag:       boolPtr(false),
+				MaybeID:    intPtr(0),
+				IDs:        []int{1, 2},
+				Tags:       []string{"a", "b"},
+				Since:      since,
+				On:         since,
+				Status:     "open",
+				Skipped:    "x",
+				Untagged:   "x",
+				unexported: "x",
+			},
+			want: url.Values{
+				"name":        {"north"},
+				"count":       {"3"},
+				"limit":       {"10"},
+				"ratio":       {"0.5"},
+				"active":      {"true"},
+				"flag":        {"false"}, // a set pointer is sent even if it points at a zero value
+				"maybe_id":    {"0"},
+				"ids":         {"1", "2"},
+				"tags[]":      {"a", "b"},
+				"since":       {"2024-03-01T09:30:00Z"},
+				"on":          {"2024-03-01"},
+				"status_name": {"open"},
+			},
+		},
+		{
+			name: "embedded CommonListOptions",
+			opts: &model.JobListOptions{
+				CommonListOptions: model.CommonListOptions{
+					ListOptions:  model.ListOptions{Page: 2, PerPage: 50},
+					Keyword:      strPtr("ACME"),
+					State:        strPtr("pending"),
+					Archived:     boolPtr(false),
+					Date:         strPtr("2024-03-01"),
+					FromDateTime: strPtr("2024-03-01T00:00:00+08:00"),
+					ToDateTime:   strPtr("2024-03-02T00:00:00+08:00"),
+					SortBy:       strPtr("created_at"),
+					OrderBy:      strPtr("desc"),
+				},
+				CustomerID: intPtr(7),
+			},
+			want: url.Values{
+				"page":          {"2"},
+				"per_page":      {"50"},
+				"keyword":       {"ACME"},
+				"state":         {"pending"},
+				"archived":      {"false"},
+				"date":          {"2024-03-01"},
+				"from_datetime": {"2024-03-01T00:00:00+08:00"},
+				"to_datetime":   {"2024-03-02T00:00:00+08:00"},
+				"sort_by":       {"created_at"},
+				"order_by":      {"desc"},
+				"customer_id":   {"7"},
+			},
+		},
+		{
+			name: "omitted embedded fields",
+			opts: &model.TaskListOptions{TrackingID: strPtr("TRK-1")},
+			want: url.Values{"tracking_id": {"TRK-1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.EncodeQuery(tt.opts)
+			if err != nil {
+				t.Fatalf("EncodeQuery: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EncodeQuery =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeQueryRejectsNonStruct(t *testing.T) {
+	if _, err := client.EncodeQuery(map[string]string{"a": "b"}); err == nil {
+		t.Error("EncodeQuery(map) succeeded, want error")
+	}
+}
+
+func TestEncodeQueryUnsupportedField(t *testing.T) {
+	opts := struct {
+		Ch chan int `url:"ch"`
+	}{Ch: make(chan int)}
+	if _, err := client.EncodeQuery(opts); err == nil {
+		t.Error("EncodeQuery(chan field) succeeded, want error")
+	}
+}
+
+func strPtr(s string) *string { return &s }
+func intPtr(i int) *int       { return &i }
+func boolPtr(b bool) *bool    { return &b }
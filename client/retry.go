@@ -0,0 +1,183 @@
+package client
+
+import (
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+	"github.com/go-resty/resty/v2"
+)
+
+// IdempotencyKeyHeader marks a request as safe to retry even when its method isn't idempotent
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// errRetryAfterTooLong stops retrying when the server asks for a longer wait than the policy allows
+var errRetryAfterTooLong = errors.New("versafleet-sdk: Retry-After exceeds MaxRetryAfter")
+
+// RetryPolicy decides which failed requests are retried and how long to wait between attempts.
+// Whatever the policy, a 401 is retried once with a fresh token in OAuth2 mode.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. 1 disables retries.
+	MaxAttempts int
+
+	// Waits grow exponentially from MinWait up to MaxWait, with jitter
+	MinWait time.Duration
+	MaxWait time.Duration
+
+	// RetryableStatus reports whether a response status is worth retrying.
+	// Network errors (no response) are always retryable.
+	RetryableStatus func(statusCode int) bool
+
+	// RetryAfter reads how long the server asked to wait. It takes precedence over the backoff.
+	RetryAfter func(header http.Header) (time.Duration, bool)
+	// MaxRetryAfter is the longest server-requested wait honoured; a longer one ends the retries
+	MaxRetryAfter time.Duration
+
+	// RetryNonIdempotent retries POST and PATCH requests even without a caller-supplied Idempotency-Key header.
+	// Leave it off unless duplicates are harmless: a timed-out create may have succeeded.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy makes up to 4 attempts, 500ms to 2s apart, on network errors, 429 and 5xx
+// gateway errors, honouring Retry-After up to a minute. Only idempotent methods and requests
+// with an Idempotency-Key header are retried, the latter only if no local IdempotencyStore is set.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     4,
+		MinWait:         500 * time.Millisecond,
+		MaxWait:         2 * time.Second,
+		RetryableStatus: DefaultRetryableStatus,
+		RetryAfter: func(h http.Header) (time.Duration, bool) {
+			return rate.ParseRetryAfter(h, time.Now())
+		},
+		MaxRetryAfter: time.Minute,
+	}
+}
+
+// DefaultRetryableStatus retries 429 and the 5xx statuses that usually mean a transient failure
+func DefaultRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// isIdempotent reports whether method can be repeated without changing the result (RFC 9110)
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// configureRetries applies the retry policy to the resty client
+func (c *Client) configureRetries() {
+	p := &c.retry
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = 1
+	}
+	if p.RetryableStatus == nil {
+		p.RetryableStatus = DefaultRetryableStatus
+	}
+
+	// At least one retry, for the 401 token refresh
+	c.http.SetRetryCount(max(p.MaxAttempts-1, 1))
+	// retryWait computes the full wait; these only stop resty from clamping it
+	c.http.SetRetryWaitTime(0)
+	c.http.SetRetryMaxWaitTime(max(p.MaxWait, p.MaxRetryAfter))
+	c.http.SetRetryAfter(c.retryWait)
+	c.http.AddRetryCondition(c.shouldRetry)
+}
+
+// shouldRetry is the resty retry condition implementing the retry policy
+func (c *Client) shouldRetry(resp *resty.Response, err error) bool {
+	if resp == nil {
+		// Failed before the request was sent (e.g. token fetch), nothing to retry
+		return false
+	}
+	if resp.StatusCode() == http.StatusUnauthorized && c.usesOAuth2() {
+		return c.retryUnauthorized(resp)
+	}
+
+	req := resp.Request
+	if req.Attempt >= c.retry.MaxAttempts {
+		return false
+	}
+	if !isIdempotent(req.Method) && !c.retry.RetryNonIdempotent && !c.keyMakesRetrySafe(req) {
+		return false
+	}
+	if resp.RawResponse == nil {
+		// Network error
+		return err != nil
+	}
+	return c.retry.RetryableStatus(resp.StatusCode())
+}
+
+// keyMakesRetrySafe reports whether the API deduplicates req by its idempotency key.
+// Services only generate keys for creates when a local IdempotencyStore is set, so without
+// one a key came from the caller. With a store the API is assumed not to deduplicate, and
+// the service retries through the store instead.
+func (c *Client) keyMakesRetrySafe(req *resty.Request) bool {
+	return req.Header.Get(IdempotencyKeyHeader) != "" && c.idempotency == nil
+}
+
+// retryWait is the resty RetryAfter callback. It logs each retry with its wait.
+func (c *Client) retryWait(_ *resty.Client, resp *resty.Response) (time.Duration, error) {
+	wait, err := c.backoff(resp)
+	req := resp.Request
+	attrs := []slog.Attr{
+		slog.String("operation", OperationFrom(req.Context())),
+		slog.String("method", req.Method),
+		slog.String("url", req.URL),
+		slog.Int("attempt", req.Attempt),
+	}
+	if resp.RawResponse != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode()))
+	}
+	if err != nil {
+		c.log.LogAttrs(req.Context(), slog.LevelWarn, "versafleet-sdk: not retrying, Retry-After exceeds MaxRetryAfter", attrs...)
+		return 0, err
+	}
+	c.log.LogAttrs(req.Context(), slog.LevelInfo, "versafleet-sdk: retrying", append(attrs, slog.Duration("wait", wait))...)
+	return wait, nil
+}
+
+// backoff is how long to wait before retrying resp's request: Retry-After when the server sent it,
+// otherwise exponential backoff with jitter
+func (c *Client) backoff(resp *resty.Response) (time.Duration, error) {
+	p := c.retry
+	if resp.RawResponse != nil && p.RetryAfter != nil {
+		if wait, ok := p.RetryAfter(resp.Header()); ok {
+			if p.MaxRetryAfter > 0 && wait > p.MaxRetryAfter {
+				return 0, errRetryAfterTooLong
+			}
+			// resty treats 0 as "use your own backoff"
+			return max(wait, time.Nanosecond), nil
+		}
+	}
+
+	attempt := max(resp.Request.Attempt, 1)
+	wait := p.MinWait << (attempt - 1)
+	if wait <= 0 || wait > p.MaxWait {
+		wait = p.MaxWait
+	}
+	// Equal jitter: half fixed, half random
+	half := wait / 2
+	if half > 0 {
+		wait = half + rand.N(half)
+	}
+	return max(wait, time.Nanosecond), nil
+}
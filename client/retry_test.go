@@ -0,0 +1,200 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+	"github.com/Willias7788/go-versafleet-sdk/versafleettest"
+)
+
+// fastPolicy is the default policy with millisecond waits
+func fastPolicy() client.RetryPolicy {
+	p := client.DefaultRetryPolicy()
+	p.MinWait, p.MaxWait = time.Millisecond, 5*time.Millisecond
+	return p
+}
+
+func newRetryClient(srv *versafleettest.Server, policy client.RetryPolicy, opts ...client.Option) *client.Client {
+	opts = append([]client.Option{
+		client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100})),
+		client.WithRetryPolicy(policy),
+	}, opts...)
+	return client.New(srv.Config(), opts...)
+}
+
+// attempts counts the requests the fake received for method and path
+func attempts(srv *versafleettest.Server, method, path string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r.Method == method && r.URL.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRetryableStatusMatrix(t *testing.T) {
+	tests := []struct {
+		status int
+		retry  bool
+	}{
+		{http.StatusBadRequest, false},
+		{http.StatusNotFound, false},
+		{http.StatusConflict, false},
+		{http.StatusUnprocessableEntity, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusNotImplemented, false},
+		{http.StatusBadGateway, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusGatewayTimeout, true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			srv := versafleettest.NewServer()
+			defer srv.Close()
+			customer := srv.AddCustomer(model.Customer{Name: "ACME"})
+			path := fmt.Sprintf("/customers/%d", customer.ID)
+			srv.InjectFault(versafleettest.Fault{Status: tt.status, Count: 1, PathPrefix: path})
+
+			err := newRetryClient(srv, fastPolicy()).Get(context.Background(), path, &model.CustomerDetail{})
+			want := 1
+			if tt.retry {
+				want = 2
+			}
+			if got := attempts(srv, http.MethodGet, path); got != want {
+				t.Errorf("%d attempts, want %d", got, want)
+			}
+			if tt.retry != (err == nil) {
+				t.Errorf("err = %v, retried = %v", err, tt.retry)
+			}
+		})
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		key    bool
+		policy func(*client.RetryPolicy)
+		opts   []client.Option
+		want   int
+	}{
+		{"POST without key", http.MethodPost, false, nil, nil, 1},
+		{"POST with key", http.MethodPost, true, nil, nil, 2},
+		{"POST with key and a local store", http.MethodPost, true, nil, []client.Option{client.WithIdempotencyStore(client.NewMemoryIdempotencyStore(time.Hour))}, 1},
+		{"POST with RetryNonIdempotent", http.MethodPost, false, func(p *client.RetryPolicy) { p.RetryNonIdempotent = true }, nil, 2},
+		{"PUT without key", http.MethodPut, false, nil, nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := versafleettest.NewServer()
+			defer srv.Close()
+			customer := srv.AddCustomer(model.Customer{Name: "ACME"})
+			path := "/customers"
+			if tt.method == http.MethodPut {
+				path = fmt.Sprintf("/customers/%d", customer.ID)
+			}
+			srv.InjectFault(versafleettest.Fault{Status: http.StatusServiceUnavailable, Count: 1, Method: tt.method, PathPrefix: path})
+
+			policy := fastPolicy()
+			if tt.policy != nil {
+				tt.policy(&policy)
+			}
+			c := newRetryClient(srv, policy, tt.opts...)
+			ctx := context.Background()
+			if tt.key {
+				ctx = client.WithIdempotencyKey(ctx, "key-1")
+			}
+
+			body := &model.Customer{Name: "ACME Pte Ltd"}
+			var err error
+			if tt.method == http.MethodPost {
+				err = c.Post(ctx, path, body, &model.Customer{})
+			} else {
+				err = c.Put(ctx, path, body, &model.Customer{})
+			}
+			if got := attempts(srv, tt.method, path); got != tt.want {
+				t.Errorf("%d attempts, want %d", got, tt.want)
+			}
+			if (tt.want == 2) != (err == nil) {
+				t.Errorf("err = %v", err)
+			}
+		})
+	}
+}
+
+func TestRetryAfterCap(t *testing.T) {
+	tests := []struct {
+		name          string
+		retryAfter    time.Duration
+		maxRetryAfter time.Duration
+		want          int
+	}{
+		{"within the cap", time.Second, 2 * time.Second, 2},
+		{"over the cap", 3 * time.Second, 2 * time.Second, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := versafleettest.NewServer()
+			defer srv.Close()
+			customer := srv.AddCustomer(model.Customer{Name: "ACME"})
+			path := fmt.Sprintf("/customers/%d", customer.ID)
+			srv.InjectFault(versafleettest.Fault{Status: http.StatusTooManyRequests, Count: 1, PathPrefix: path, RetryAfter: tt.retryAfter})
+
+			policy := fastPolicy()
+			policy.MaxRetryAfter = tt.maxRetryAfter
+			start := time.Now()
+			err := newRetryClient(srv, policy).Get(context.Background(), path, &model.CustomerDetail{})
+			elapsed := time.Since(start)
+
+			if got := attempts(srv, http.MethodGet, path); got != tt.want {
+				t.Errorf("%d attempts, want %d", got, tt.want)
+			}
+			if tt.want == 2 {
+				if err != nil {
+					t.Errorf("Get: %v", err)
+				}
+				if elapsed < tt.retryAfter-50*time.Millisecond {
+					t.Errorf("retried after %v, want the server's %v", elapsed, tt.retryAfter)
+				}
+			} else {
+				if err == nil {
+					t.Error("Get succeeded without retrying")
+				}
+				if elapsed > time.Second {
+					t.Errorf("gave up after %v, want no wait", elapsed)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	for _, maxAttempts := range []int{1, 2, 4} {
+		t.Run(fmt.Sprint(maxAttempts), func(t *testing.T) {
+			srv := versafleettest.NewServer()
+			defer srv.Close()
+			customer := srv.AddCustomer(model.Customer{Name: "ACME"})
+			path := fmt.Sprintf("/customers/%d", customer.ID)
+			srv.InjectFault(versafleettest.Fault{Status: http.StatusServiceUnavailable, PathPrefix: path})
+
+			policy := fastPolicy()
+			policy.MaxAttempts = maxAttempts
+			err := newRetryClient(srv, policy).Get(context.Background(), path, &model.CustomerDetail{})
+			if err == nil {
+				t.Fatal("Get succeeded against a failing server")
+			}
+			if got := attempts(srv, http.MethodGet, path); got != maxAttempts {
+				t.Errorf("%d attempts, want %d", got, maxAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Package telemetry instruments client.Client with OpenTelemetry traces and metrics.
+//
+//	c := client.New(cfg, client.WithMiddleware(telemetry.Middleware()))
+//
+// Every call gets a span named after its operation (tasks.List, jobs.Create, ...).
+// Calls that aren't named (plain R() requests) get a span named after their method,
+// and count under UnnamedOperation in metrics, so raw IDs in paths don't end up as metric attributes.
+// Without options the global tracer and meter providers are used, which are no-ops
+// until the application installs real ones.
+package telemetry
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter
+const ScopeName = "github.com/Willias7788/go-versafleet-sdk/client/telemetry"
+
+// Attribute keys set on spans and metrics
+const (
+	AttrOperation   = attribute.Key("versafleet.operation")
+	AttrRequestID   = attribute.Key("versafleet.request_id")
+	AttrPage        = attribute.Key("versafleet.page")
+	AttrRetryCount  = attribute.Key("versafleet.retry_count")
+	AttrLimiterWait = attribute.Key("versafleet.rate_limiter.wait_ms")
+	AttrMethod      = attribute.Key("http.request.method")
+	AttrPath        = attribute.Key("url.path")
+	AttrStatusCode  = attribute.Key("http.response.status_code")
+)
+
+// UnnamedOperation is the operation attribute of calls made without client.WithOperation
+const UnnamedOperation = "unnamed"
+
+// secondBuckets are histogram boundaries for durations in seconds; the SDK default is meant for milliseconds
+var secondBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Option configures Middleware
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// WithTracerProvider uses tp instead of the global tracer provider
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = tp }
+}
+
+// WithMeterProvider uses mp instead of the global meter provider
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = mp }
+}
+
+type instruments struct {
+	tracer      trace.Tracer
+	duration    metric.Float64Histogram
+	errors      metric.Int64Counter
+	limiterWait metric.Float64Histogram
+	pages       metric.Int64Counter
+}
+
+// Middleware returns client middleware that records a span and metrics for every call:
+//
+//   - versafleet.client.request.duration (s): call duration, including retries
+//   - versafleet.client.request.errors: failed calls, including 4xx and 5xx responses, by status code (0 for network errors)
+//   - versafleet.client.rate_limiter.wait (s): time spent waiting on the rate limiter
+//   - versafleet.client.iterator.pages: pages fetched by iterators
+//
+// Instrument creation errors are reported to otel.Handle and the failing instrument is skipped.
+func Middleware(opts ...Option) client.Middleware {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+	var ins instruments
+	var errs []error
+	var err error
+	ins.tracer = cfg.tracerProvider.Tracer(ScopeName)
+	ins.duration, err = meter.Float64Histogram("versafleet.client.request.duration",
+		metric.WithUnit("s"), metric.WithDescription("Duration of VersaFleet API calls, including retries"),
+		metric.WithExplicitBucketBoundaries(secondBuckets...))
+	errs = append(errs, err)
+	ins.errors, err = meter.Int64Counter("versafleet.client.request.errors",
+		metric.WithDescription("Failed VersaFleet API calls by status code"))
+	errs = append(errs, err)
+	ins.limiterWait, err = meter.Float64Histogram("versafleet.client.rate_limiter.wait",
+		metric.WithUnit("s"), metric.WithDescription("Time VersaFleet API calls waited on the rate limiter"),
+		metric.WithExplicitBucketBoundaries(secondBuckets...))
+	errs = append(errs, err)
+	ins.pages, err = meter.Int64Counter("versafleet.client.iterator.pages",
+		metric.WithDescription("Pages fetched by VersaFleet list iterators"))
+	errs = append(errs, err)
+	if err := errors.Join(errs...); err != nil {
+		otel.Handle(err)
+	}
+
+	return func(next client.RoundTripper) client.RoundTripper {
+		return client.RoundTripperFunc(func(call *client.Call) (*client.Response, error) {
+			return ins.roundTrip(next, call)
+		})
+	}
+}
+
+func (ins *instruments) roundTrip(next client.RoundTripper, call *client.Call) (*client.Response, error) {
+	// An unnamed call's Operation is "METHOD /path", which may hold IDs
+	operation, spanName := call.Operation, call.Operation
+	if client.OperationFrom(call.Context()) == "" {
+		operation, spanName = UnnamedOperation, call.Method
+	}
+	attrs := []attribute.KeyValue{
+		AttrOperation.String(operation),
+		AttrMethod.String(call.Method),
+		AttrPath.String(call.Path),
+	}
+	if call.Page > 0 {
+		attrs = append(attrs, AttrPage.Int(call.Page))
+	}
+
+	ctx, span := ins.tracer.Start(call.Context(), spanName,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	start := time.Now()
+	resp, err := next.RoundTrip(call.WithContext(ctx))
+	elapsed := time.Since(start)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+		if status > 0 {
+			span.SetAttributes(AttrStatusCode.Int(status))
+		}
+		if id := resp.Header.Get("X-Request-Id"); id != "" {
+			span.SetAttributes(AttrRequestID.String(id))
+		}
+		if resp.Attempts > 0 {
+			span.SetAttributes(AttrRetryCount.Int(resp.Attempts - 1))
+		}
+		if resp.LimiterWait > 0 {
+			span.SetAttributes(AttrLimiterWait.Int64(resp.LimiterWait.Milliseconds()))
+		}
+	}
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		status = apiErr.StatusCode
+		if apiErr.RequestID != "" {
+			span.SetAttributes(AttrRequestID.String(apiErr.RequestID))
+		}
+	}
+	// R() requests get error statuses back as responses, not errors
+	failed := err != nil || status >= 400
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case failed:
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+
+	metricAttrs := metric.WithAttributes(AttrOperation.String(operation), AttrStatusCode.Int(status))
+	if ins.duration != nil {
+		ins.duration.Record(ctx, elapsed.Seconds(), metricAttrs)
+	}
+	if failed && ins.errors != nil {
+		ins.errors.Add(ctx, 1, metricAttrs)
+	}
+	if resp != nil && ins.limiterWait != nil {
+		ins.limiterWait.Record(ctx, resp.LimiterWait.Seconds(), metric.WithAttributes(AttrOperation.String(operation)))
+	}
+	if call.Page > 0 && !failed && ins.pages != nil {
+		ins.pages.Add(ctx, 1, metric.WithAttributes(AttrOperation.String(operation)))
+	}
+	return resp, err
+}
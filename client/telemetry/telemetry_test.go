@@ -0,0 +1,210 @@
+package telemetry_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/client/telemetry"
+	"github.com/Willias7788/go-versafleet-sdk/customers"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+	"github.com/Willias7788/go-versafleet-sdk/versafleettest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recorder struct {
+	spans  *tracetest.InMemoryExporter
+	reader *sdkmetric.ManualReader
+}
+
+// newClient returns a client instrumented with in-memory trace and metric providers
+func newClient(srv *versafleettest.Server) (*client.Client, *recorder) {
+	rec := &recorder{spans: tracetest.NewInMemoryExporter(), reader: sdkmetric.NewManualReader()}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(rec.spans))
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(rec.reader))
+	c := client.New(srv.Config(),
+		client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100})),
+		client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}),
+		client.WithMiddleware(telemetry.Middleware(telemetry.WithTracerProvider(tp), telemetry.WithMeterProvider(mp))),
+	)
+	return c, rec
+}
+
+// points returns the data points of the named metric as "operation status" -> count,
+// with status left out for metrics that don't carry one
+func (r *recorder) points(t *testing.T, name string) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := r.reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	key := func(set attribute.Set) string {
+		op, _ := set.Value(telemetry.AttrOperation)
+		if status, ok := set.Value(telemetry.AttrStatusCode); ok {
+			return fmt.Sprintf("%s %d", op.AsString(), status.AsInt64())
+		}
+		return op.AsString()
+	}
+	got := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					got[key(dp.Attributes)] += dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					got[key(dp.Attributes)] += int64(dp.Count)
+				}
+			}
+		}
+	}
+	return got
+}
+
+func attrs(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestNamedCall(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	c, rec := newClient(srv)
+	customer := srv.AddCustomer(model.Customer{Name: "ACME"})
+	svc := customers.New(c)
+
+	if _, err := svc.Get(context.Background(), fmt.Sprint(customer.ID)); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := svc.Get(context.Background(), "999999"); err == nil {
+		t.Fatal("Get of a missing customer succeeded")
+	}
+
+	spans := rec.spans.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	ok, missing := spans[0], spans[1]
+	if ok.Name != "customers.Get" || ok.SpanKind != trace.SpanKindClient {
+		t.Errorf("span %q kind %v, want customers.Get client", ok.Name, ok.SpanKind)
+	}
+	got := attrs(ok.Attributes)
+	want := map[attribute.Key]attribute.Value{
+		telemetry.AttrOperation:  attribute.StringValue("customers.Get"),
+		telemetry.AttrMethod:     attribute.StringValue(http.MethodGet),
+		telemetry.AttrPath:       attribute.StringValue(fmt.Sprintf("/customers/%d", customer.ID)),
+		telemetry.AttrStatusCode: attribute.IntValue(200),
+		telemetry.AttrRetryCount: attribute.IntValue(0),
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k].Emit(), v.Emit())
+		}
+	}
+	if ok.Status.Code != codes.Unset {
+		t.Errorf("successful span status %v", ok.Status)
+	}
+	if missing.Status.Code != codes.Error || attrs(missing.Attributes)[telemetry.AttrStatusCode] != attribute.IntValue(404) {
+		t.Errorf("404 span status %v, attributes %v", missing.Status, missing.Attributes)
+	}
+	if len(missing.Events) == 0 || missing.Events[0].Name != "exception" {
+		t.Errorf("404 span didn't record the error: %v", missing.Events)
+	}
+
+	if got := rec.points(t, "versafleet.client.request.duration"); got["customers.Get 200"] != 1 || got["customers.Get 404"] != 1 || len(got) != 2 {
+		t.Errorf("duration counts = %v", got)
+	}
+	if got := rec.points(t, "versafleet.client.request.errors"); got["customers.Get 404"] != 1 || len(got) != 1 {
+		t.Errorf("error counts = %v, want one 404", got)
+	}
+}
+
+func TestUnnamedRequestErrorStatus(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	c, rec := newClient(srv)
+	customer := srv.AddCustomer(model.Customer{Name: "ACME"})
+	srv.InjectFault(versafleettest.Fault{Status: http.StatusServiceUnavailable, Count: 1, PathPrefix: "/customers"})
+
+	path := fmt.Sprintf("/customers/%d", customer.ID)
+	for _, want := range []int{503, 200} {
+		resp, err := c.R(context.Background()).Get(path)
+		if resp == nil || resp.StatusCode() != want || (err != nil) != (want != 200) {
+			t.Fatalf("R().Get = %v, %v; want status %d", resp, err, want)
+		}
+	}
+
+	spans := rec.spans.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	for i, span := range spans {
+		got := attrs(span.Attributes)
+		if span.Name != http.MethodGet || got[telemetry.AttrOperation].AsString() != telemetry.UnnamedOperation {
+			t.Errorf("span %d named %q with operation %q", i, span.Name, got[telemetry.AttrOperation].AsString())
+		}
+		// The path, ID included, is fine on a span
+		if got[telemetry.AttrPath].AsString() != path {
+			t.Errorf("span %d path %q, want %q", i, got[telemetry.AttrPath].AsString(), path)
+		}
+	}
+	// The middleware sees the 503 as a response rather than an error, but it is still a failure
+	if spans[0].Status.Code != codes.Error || spans[1].Status.Code != codes.Unset {
+		t.Errorf("span statuses %v and %v, want Error then Unset", spans[0].Status, spans[1].Status)
+	}
+
+	if got := rec.points(t, "versafleet.client.request.errors"); got["unnamed 503"] != 1 || len(got) != 1 {
+		t.Errorf("error counts = %v, want one unnamed 503", got)
+	}
+	duration := rec.points(t, "versafleet.client.request.duration")
+	// Nothing else, so no series per customer ID
+	if duration["unnamed 503"] != 1 || duration["unnamed 200"] != 1 || len(duration) != 2 {
+		t.Errorf("duration counts = %v", duration)
+	}
+}
+
+func TestIteratorPages(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	c, rec := newClient(srv)
+	for i := 0; i < 3; i++ {
+		srv.AddCustomer(model.Customer{Name: fmt.Sprintf("Customer %d", i)})
+	}
+
+	opts := &model.CustomerListOptions{CommonListOptions: model.CommonListOptions{ListOptions: model.ListOptions{PerPage: 2}}}
+	it := customers.New(c).List(context.Background(), opts)
+	defer it.Close()
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if err := it.Err(); err != nil || n != 3 {
+		t.Fatalf("listed %d customers, err %v; want 3", n, err)
+	}
+
+	if got := rec.points(t, "versafleet.client.iterator.pages"); got["customers.List"] != 2 || len(got) != 1 {
+		t.Errorf("page counts = %v, want 2 for customers.List", got)
+	}
+	for _, span := range rec.spans.GetSpans() {
+		if page := attrs(span.Attributes)[telemetry.AttrPage]; span.Name != "customers.List" || page.AsInt64() < 1 {
+			t.Errorf("span %q has page %v", span.Name, page.Emit())
+		}
+	}
+}
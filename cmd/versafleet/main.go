@@ -0,0 +1,191 @@
+// Command versafleet is a developer tool for the VersaFleet SDK.
+//
+// Usage:
+//
+//	versafleet webhook send [flags] [fixture.json ...]
+//
+// "webhook send" posts correctly signed webhook deliveries to a local endpoint.
+// Events come from fixture files, from a task/job JSON file (-task-file, -job-file),
+// or are generated from -type and -id.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/model"
+	"github.com/Willias7788/go-versafleet-sdk/webhooks"
+	"github.com/Willias7788/go-versafleet-sdk/webhooks/webhooktest"
+)
+
+const usage = `Usage:
+  versafleet webhook send [flags] [fixture.json ...]
+
+Run "versafleet webhook send -h" for flags.
+`
+
+func main() {
+	if len(os.Args) < 3 || os.Args[1] != "webhook" || os.Args[2] != "send" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err := webhookSend(os.Args[3:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func webhookSend(args []string) error {
+	fs := flag.NewFlagSet("webhook send", flag.ExitOnError)
+	url := fs.String("url", "http://localhost:8080/webhooks", "endpoint to POST deliveries to")
+	secret := fs.String("secret", os.Getenv("VERSAFLEET_WEBHOOK_SECRET"), "signing secret (default $VERSAFLEET_WEBHOOK_SECRET)")
+	eventType := fs.String("type", "", "event type to generate, or of the -task-file/-job-file event (default task.completed, or job.completed with -job-file)")
+	id := fs.Int("id", 1, "task/job ID of the generated event")
+	taskFile := fs.String("task-file", "", "JSON file with a model.Task to send as a task.* event")
+	jobFile := fs.String("job-file", "", "JSON file with a model.Job to send as a job.* event")
+	retries := fs.Int("retries", 0, "resend a delivery that didn't get a 2xx this many times")
+	retryWait := fs.Duration("retry-wait", time.Second, "wait between retries")
+	duplicates := fs.Int("duplicates", 0, "send every delivery this many extra times")
+	badSignature := fs.Bool("bad-signature", false, "corrupt the signature")
+	shuffle := fs.Bool("shuffle", false, "send events out of order (duplicates still follow their original)")
+	timeout := fs.Duration("timeout", 30*time.Second, "overall timeout")
+	_ = fs.Parse(args)
+
+	if *secret == "" && !*badSignature {
+		return fmt.Errorf("-secret or VERSAFLEET_WEBHOOK_SECRET is required")
+	}
+
+	var events []*webhooks.Event
+	for _, path := range fs.Args() {
+		event, err := webhooktest.LoadFixture(path)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+
+	t, err := eventTypeFor(webhooks.EventType(*eventType), *taskFile, *jobFile)
+	if err != nil {
+		return err
+	}
+	switch {
+	case *taskFile != "":
+		var task model.Task
+		if err := readJSON(*taskFile, &task); err != nil {
+			return err
+		}
+		event, err := webhooktest.TaskEvent(t, task)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	case *jobFile != "":
+		var job model.Job
+		if err := readJSON(*jobFile, &job); err != nil {
+			return err
+		}
+		event, err := webhooktest.JobEvent(t, job)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	case len(events) == 0:
+		event, err := generate(t, *id)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	sender := &webhooktest.Sender{
+		URL:          *url,
+		Secret:       *secret,
+		Retries:      *retries,
+		RetryWait:    *retryWait,
+		Duplicates:   *duplicates,
+		BadSignature: *badSignature,
+		Shuffle:      *shuffle,
+	}
+	results, err := sender.Send(ctx, events...)
+	for _, r := range results {
+		status := fmt.Sprint(r.StatusCode)
+		if r.Err != nil {
+			status = r.Err.Error()
+		}
+		dup := ""
+		if r.Duplicate {
+			dup = " (duplicate)"
+		}
+		fmt.Printf("%s %s attempt=%d%s -> %s\n", r.EventID, r.EventType, r.Attempt, dup, status)
+	}
+	return err
+}
+
+// eventTypeFor picks the event type for -type, defaulting to a completed event of whichever
+// kind -task-file or -job-file asks for, and checks that the two agree
+func eventTypeFor(t webhooks.EventType, taskFile, jobFile string) (webhooks.EventType, error) {
+	kind := ""
+	switch {
+	case taskFile != "" && jobFile != "":
+		return "", fmt.Errorf("-task-file and -job-file can't be used together")
+	case taskFile != "":
+		kind = "task."
+	case jobFile != "":
+		kind = "job."
+	}
+	if t == "" {
+		if kind == "job." {
+			return webhooks.EventTypeJobCompleted, nil
+		}
+		return webhooks.EventTypeTaskCompleted, nil
+	}
+	if kind != "" && !strings.HasPrefix(string(t), kind) {
+		return "", fmt.Errorf("-type %s doesn't match the %s* event of -%sfile", t, kind, strings.TrimSuffix(kind, ".")+"-")
+	}
+	return t, nil
+}
+
+// generate builds a minimal event of type t for the task or job with the given ID
+func generate(t webhooks.EventType, id int) (*webhooks.Event, error) {
+	switch {
+	case strings.HasPrefix(string(t), "task."):
+		task := model.Task{ID: id, State: taskStates[t]}
+		return webhooktest.TaskEvent(t, task)
+	case strings.HasPrefix(string(t), "job."):
+		return webhooktest.JobEvent(t, model.Job{ID: id})
+	}
+	return webhooktest.NewEvent(t, map[string]int{"id": id})
+}
+
+// taskStates gives generated task events a state consistent with their type
+var taskStates = map[webhooks.EventType]model.TaskState{
+	webhooks.EventTypeTaskCreated:    model.TaskStateUnassigned,
+	webhooks.EventTypeTaskAssigned:   model.TaskStateAssigned,
+	webhooks.EventTypeTaskUnassigned: model.TaskStateUnassigned,
+	webhooks.EventTypeTaskAccepted:   model.TaskStateAccepted,
+	webhooks.EventTypeTaskDeclined:   model.TaskStateDeclined,
+	webhooks.EventTypeTaskStarted:    model.TaskStateStarted,
+	webhooks.EventTypeTaskArrived:    model.TaskStateArrived,
+	webhooks.EventTypeTaskCompleted:  model.TaskStateSuccessful,
+	webhooks.EventTypeTaskFailed:     model.TaskStateFailed,
+	webhooks.EventTypeTaskCancelled:  model.TaskStateCancelled,
+}
+
+func readJSON(path string, v interface{}) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
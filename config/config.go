@@ -1,20 +1,67 @@
 package config
 
 import (
-	"fmt"
+	"log/slog"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
+// AuthMode selects how the client presents its credentials to the API.
+// Empty means AuthModeOAuth2, both from Load and in a hand-built Config.
+type AuthMode string
+
+// DefaultTokenURL is the OAuth2 token endpoint used when TokenURL is empty
+const DefaultTokenURL = "/oauth/token"
+
+const (
+	// AuthModeOAuth2 exchanges the client credentials for a bearer token
+	AuthModeOAuth2 AuthMode = "oauth2"
+	// AuthModeQuery sends client_id and client_secret as query parameters on every request.
+	// Kept for tenants that have not been moved to OAuth2 yet.
+	AuthModeQuery AuthMode = "query"
+)
+
 type Config struct {
-	BaseURL      string `mapstructure:"base_url"`
-	ClientID     string `mapstructure:"client_id"`
-	ClientSecret string `mapstructure:"client_secret"`
-	Debug        bool   `mapstructure:"debug"`
+	BaseURL      string   `mapstructure:"base_url"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	AuthMode     AuthMode `mapstructure:"auth_mode"`
+	TokenURL     string   `mapstructure:"token_url"` // Relative to BaseURL unless absolute; DefaultTokenURL if empty
+	Debug        bool     `mapstructure:"debug"`
+
+	// HTTP settings, each matching a client option; options passed to client.New take precedence
+
+	Timeout           time.Duration `mapstructure:"timeout"`            // Per attempt, 1m if zero
+	OperationTimeouts []string      `mapstructure:"operation_timeouts"` // "operation=duration" entries, e.g. "jobs.Create=30s"
+	UserAgent         string        `mapstructure:"user_agent"`
+	Headers           []string      `mapstructure:"headers"` // "Name: value" entries sent on every request
+	ProxyURL          string        `mapstructure:"proxy_url"`
+
+	TLSCAFile             string `mapstructure:"tls_ca_file"` // PEM CA bundle trusted on top of the system roots
+	TLSCertFile           string `mapstructure:"tls_cert_file"`
+	TLSKeyFile            string `mapstructure:"tls_key_file"`
+	TLSInsecureSkipVerify bool   `mapstructure:"tls_insecure_skip_verify"`
 }
 
+// Load reads the config from VERSAFLEET_* environment variables and an optional .env file.
+// It logs nothing; use LoadWithLogger to see which file was used.
 func Load() (*Config, error) {
+	return LoadWithLogger(nil)
+}
+
+// quotedLine matches the line quoted in a .env parse error
+var quotedLine = regexp.MustCompile("`[^`]*`")
+
+// LoadWithLogger is Load, logging at Debug to logger (if not nil) whether a .env file was found.
+// A .env file that can't be read is logged at Warn and skipped, leaving env vars and defaults.
+func LoadWithLogger(logger *slog.Logger) (*Config, error) {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
 	viper.SetEnvPrefix("VERSAFLEET")
 	viper.AutomaticEnv()
 
@@ -23,7 +70,19 @@ func Load() (*Config, error) {
 
 	// Set defaults
 	viper.SetDefault("base_url", "https://api.versafleet.co/api")
+	viper.SetDefault("auth_mode", string(AuthModeOAuth2))
+	viper.SetDefault("token_url", DefaultTokenURL)
 	viper.SetDefault("debug", false)
+	// The rest default to zero values but must be known to viper for env vars to reach them
+	viper.SetDefault("timeout", time.Minute)
+	viper.SetDefault("operation_timeouts", []string{})
+	viper.SetDefault("user_agent", "")
+	viper.SetDefault("headers", []string{})
+	viper.SetDefault("proxy_url", "")
+	viper.SetDefault("tls_ca_file", "")
+	viper.SetDefault("tls_cert_file", "")
+	viper.SetDefault("tls_key_file", "")
+	viper.SetDefault("tls_insecure_skip_verify", false)
 
 	// Allow reading from a .env file if present
 	viper.SetConfigName(".env")
@@ -32,24 +91,21 @@ func Load() (*Config, error) {
 	err := viper.ReadInConfig() // Ignore error if config file not found
 	if err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			fmt.Println("No config file found (using env/defaults only)")
+			logger.Debug("versafleet-sdk: no config file found, using env/defaults only")
 		} else {
-			fmt.Printf("Config file found but error occurred: %v\n", err)
+			// Parse errors quote the offending line, which may hold a secret
+			msg := quotedLine.ReplaceAllString(err.Error(), "`[REDACTED]`")
+			logger.Warn("versafleet-sdk: failed to read config file, using env/defaults only",
+				slog.String("path", viper.ConfigFileUsed()), slog.String("error", msg))
 		}
 	} else {
-		fmt.Println("Config file successfully loaded:", viper.ConfigFileUsed())
+		logger.Debug("versafleet-sdk: loaded config file", slog.String("path", viper.ConfigFileUsed()))
 	}
 
-	// settings := viper.AllSettings()
-	// out, _ := json.MarshalIndent(settings, "", "  ")
-	// fmt.Printf("--- VIPER DEBUG ---\n%s\n-------------------\n", string(out))
-
 	var cfg Config
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, err
 	}
 
-	// fmt.Println(cfg)
-
 	return &cfg, nil
 }
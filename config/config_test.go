@@ -0,0 +1,73 @@
+package config_test
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Willias7788/go-versafleet-sdk/config"
+	"github.com/spf13/viper"
+)
+
+// loadIn runs LoadWithLogger in a fresh directory holding env as its .env file (none if empty)
+// and returns the config and the logs
+func loadIn(t *testing.T, env string) (*config.Config, string) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	dir := t.TempDir()
+	if env != "" {
+		if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(env), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cfg, err := config.LoadWithLogger(logger)
+	if err != nil {
+		t.Fatalf("LoadWithLogger: %v", err)
+	}
+	return cfg, logs.String()
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	cfg, logs := loadIn(t, "USER_AGENT=from-file\n")
+	if cfg.UserAgent != "from-file" {
+		t.Errorf("UserAgent = %q, want the .env value", cfg.UserAgent)
+	}
+	if !strings.Contains(logs, "loaded config file") {
+		t.Errorf("logs = %s", logs)
+	}
+}
+
+func TestLoadWithoutEnvFile(t *testing.T) {
+	t.Setenv("VERSAFLEET_USER_AGENT", "from-env")
+	cfg, logs := loadIn(t, "")
+	if cfg.UserAgent != "from-env" || cfg.AuthMode != config.AuthModeOAuth2 {
+		t.Errorf("UserAgent %q, AuthMode %q", cfg.UserAgent, cfg.AuthMode)
+	}
+	if !strings.Contains(logs, "level=DEBUG") || strings.Contains(logs, "level=WARN") {
+		t.Errorf("logs = %s", logs)
+	}
+}
+
+func TestLoadMalformedEnvFile(t *testing.T) {
+	t.Setenv("VERSAFLEET_USER_AGENT", "from-env")
+	cfg, logs := loadIn(t, "VERSAFLEET_CLIENT_SECRET supersecret\n")
+
+	// Env vars and defaults still apply
+	if cfg.UserAgent != "from-env" || cfg.BaseURL == "" {
+		t.Errorf("UserAgent %q, BaseURL %q", cfg.UserAgent, cfg.BaseURL)
+	}
+	if !strings.Contains(logs, "level=WARN") || !strings.Contains(logs, ".env") {
+		t.Errorf("logs = %s, want a warning naming the file", logs)
+	}
+	if strings.Contains(logs, "supersecret") {
+		t.Errorf("logs leak the malformed line: %s", logs)
+	}
+}
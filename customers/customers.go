@@ -23,18 +23,26 @@ type CustomerListResponse struct {
 
 // List returns an iterator to list all customers
 func (s *Service) List(ctx context.Context, opts *model.CustomerListOptions) *client.Iterator[model.Customer, *model.CustomerListOptions] {
-	return client.NewIterator(ctx, s.client, "/customers", opts, func(ctx context.Context, path string, opts *model.CustomerListOptions) ([]model.Customer, *model.Meta, error) {
-		var resp CustomerListResponse
-		path = fmt.Sprintf("%s?page=%d&per_page=%d", path, opts.Page, opts.PerPage)
-		if err := s.client.Get(ctx, path, &resp); err != nil {
-			return nil, nil, err
-		}
-		return resp.Customers, resp.Meta, nil
-	})
+	return client.NewIterator(ctx, s.client, "/customers", opts, s.listPage)
+}
+
+// ResumeList continues listing customers from a checkpoint saved by an earlier iterator
+func (s *Service) ResumeList(ctx context.Context, cp *client.Checkpoint) (*client.Iterator[model.Customer, *model.CustomerListOptions], error) {
+	return client.ResumeIterator(ctx, s.client, cp, s.listPage)
+}
+
+func (s *Service) listPage(ctx context.Context, path string, opts *model.CustomerListOptions) ([]model.Customer, *model.Meta, error) {
+	ctx = client.WithOperation(ctx, "customers.List")
+	var resp CustomerListResponse
+	if err := s.client.GetWithQuery(ctx, path, opts, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Customers, resp.Meta, nil
 }
 
 // Get retrieves a single customer by ID
 func (s *Service) Get(ctx context.Context, id string) (*model.CustomerDetail, error) {
+	ctx = client.WithOperation(ctx, "customers.Get")
 	var customer model.CustomerDetail
 	path := fmt.Sprintf("/customers/%s", id)
 	err := s.client.Get(ctx, path, &customer)
@@ -46,6 +54,7 @@ func (s *Service) Get(ctx context.Context, id string) (*model.CustomerDetail, er
 
 // Create creates a new customer
 func (s *Service) Create(ctx context.Context, customer *model.Customer) (*model.Customer, error) {
+	ctx = client.WithOperation(ctx, "customers.Create")
 	type CreateCustomerResponse struct {
 		Customer model.Customer `json:"customer"`
 	}
@@ -60,6 +69,7 @@ func (s *Service) Create(ctx context.Context, customer *model.Customer) (*model.
 
 // Update updates an existing customer
 func (s *Service) Update(ctx context.Context, id string, customer *model.Customer) (*model.Customer, error) {
+	ctx = client.WithOperation(ctx, "customers.Update")
 	var updatedCustomer model.Customer
 	path := fmt.Sprintf("/customers/%s", id)
 	err := s.client.Put(ctx, path, customer, &updatedCustomer)
@@ -71,6 +81,7 @@ func (s *Service) Update(ctx context.Context, id string, customer *model.Custome
 
 // Delete deletes a customer
 func (s *Service) Delete(ctx context.Context, id string) error {
+	ctx = client.WithOperation(ctx, "customers.Delete")
 	path := fmt.Sprintf("/customers/%s", id)
 	return s.client.Delete(ctx, path)
 }
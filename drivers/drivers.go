@@ -16,17 +16,8 @@ func New(c *client.Client) *Service {
 	return &Service{client: c}
 }
 
-type Driver struct {
-	ID           int64  `json:"id"`
-	GUID         string `json:"guid,omitempty"`
-	Name         string `json:"name"`
-	Phone        string `json:"phone"` // API uses contact_number usually, but kept Phone if mapped manually or common field
-	Username     string `json:"username,omitempty"`
-	VehicleID    string `json:"vehicle_id,omitempty"` // Note: API might send object or ID. Kept as string for now, but watch out.
-	LicensePlate string `json:"license_plate,omitempty"`
-	Status       string `json:"status,omitempty"`
-	ExternalID   string `json:"external_id,omitempty"`
-}
+// Driver is the driver payload returned by the API
+type Driver = model.Person
 
 type DriverListResponse struct {
 	Drivers []Driver    `json:"drivers"`
@@ -34,51 +25,86 @@ type DriverListResponse struct {
 }
 
 // List returns an iterator to list all drivers
-func (s *Service) List(ctx context.Context, opts *model.ListOptions) *client.Iterator[Driver, *model.ListOptions] {
-	return client.NewIterator(ctx, s.client, "/drivers", opts, func(ctx context.Context, path string, opts *model.ListOptions) ([]Driver, *model.Meta, error) {
-		var resp DriverListResponse
-		path = fmt.Sprintf("%s?page=%d&per_page=%d", path, opts.Page, opts.PerPage)
-		if err := s.client.Get(ctx, path, &resp); err != nil {
-			return nil, nil, err
-		}
-		return resp.Drivers, resp.Meta, nil
-	})
+func (s *Service) List(ctx context.Context, opts *model.DriverListOptions) *client.Iterator[Driver, *model.DriverListOptions] {
+	return client.NewIterator(ctx, s.client, "/drivers", opts, s.listPage)
+}
+
+// ResumeList continues listing drivers from a checkpoint saved by an earlier iterator
+func (s *Service) ResumeList(ctx context.Context, cp *client.Checkpoint) (*client.Iterator[Driver, *model.DriverListOptions], error) {
+	return client.ResumeIterator(ctx, s.client, cp, s.listPage)
+}
+
+func (s *Service) listPage(ctx context.Context, path string, opts *model.DriverListOptions) ([]Driver, *model.Meta, error) {
+	ctx = client.WithOperation(ctx, "drivers.List")
+	var resp DriverListResponse
+	if err := s.client.GetWithQuery(ctx, path, opts, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Drivers, resp.Meta, nil
 }
 
 // Get retrieves a single driver by ID
 func (s *Service) Get(ctx context.Context, id string) (*Driver, error) {
-	var driver Driver
+	ctx = client.WithOperation(ctx, "drivers.Get")
+	var resp model.DriverResponse
 	path := fmt.Sprintf("/drivers/%s", id)
-	err := s.client.Get(ctx, path, &driver)
+	err := s.client.Get(ctx, path, &resp)
 	if err != nil {
 		return nil, err
 	}
-	return &driver, nil
+	return &resp.Driver, nil
 }
 
 // Create creates a new driver
-func (s *Service) Create(ctx context.Context, driver *Driver) (*Driver, error) {
-	var createdDriver Driver
-	err := s.client.Post(ctx, "/drivers", driver, &createdDriver)
+func (s *Service) Create(ctx context.Context, driver *model.DriverParams) (*Driver, error) {
+	ctx = client.WithOperation(ctx, "drivers.Create")
+	var resp model.DriverResponse
+	err := s.client.Post(ctx, "/drivers", model.DriverRequest{Driver: driver}, &resp)
 	if err != nil {
 		return nil, err
 	}
-	return &createdDriver, nil
+	return &resp.Driver, nil
 }
 
 // Update updates an existing driver
-func (s *Service) Update(ctx context.Context, id string, driver *Driver) (*Driver, error) {
-	var updatedDriver Driver
+func (s *Service) Update(ctx context.Context, id string, driver *model.DriverUpdateParams) (*Driver, error) {
+	ctx = client.WithOperation(ctx, "drivers.Update")
+	var resp model.DriverResponse
 	path := fmt.Sprintf("/drivers/%s", id)
-	err := s.client.Put(ctx, path, driver, &updatedDriver)
+	err := s.client.Put(ctx, path, model.DriverUpdateRequest{Driver: driver}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Driver, nil
+}
+
+// Archive archives a driver so they can no longer be assigned to tasks
+func (s *Service) Archive(ctx context.Context, id string) (*Driver, error) {
+	ctx = client.WithOperation(ctx, "drivers.Archive")
+	var resp model.DriverResponse
+	path := fmt.Sprintf("/drivers/%s/archive", id)
+	err := s.client.Put(ctx, path, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Driver, nil
+}
+
+// Unarchive restores an archived driver
+func (s *Service) Unarchive(ctx context.Context, id string) (*Driver, error) {
+	ctx = client.WithOperation(ctx, "drivers.Unarchive")
+	var resp model.DriverResponse
+	path := fmt.Sprintf("/drivers/%s/unarchive", id)
+	err := s.client.Put(ctx, path, nil, &resp)
 	if err != nil {
 		return nil, err
 	}
-	return &updatedDriver, nil
+	return &resp.Driver, nil
 }
 
 // Delete deletes a driver
 func (s *Service) Delete(ctx context.Context, id string) error {
+	ctx = client.WithOperation(ctx, "drivers.Delete")
 	path := fmt.Sprintf("/drivers/%s", id)
 	return s.client.Delete(ctx, path)
 }
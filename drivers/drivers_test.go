@@ -0,0 +1,51 @@
+package drivers_test
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/drivers"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+	"github.com/Willias7788/go-versafleet-sdk/versafleettest"
+)
+
+func newService(srv *versafleettest.Server) *drivers.Service {
+	return drivers.New(client.New(srv.Config(), client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100}))))
+}
+
+func TestUpdateSkillList(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	svc := newService(srv)
+	ctx := context.Background()
+
+	d, err := svc.Create(ctx, &model.DriverParams{Name: "Ah Tan", SkillList: []string{"forklift", "cold chain"}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	id := strconv.Itoa(d.ID)
+
+	// Leaving SkillList nil keeps the skills
+	d, err = svc.Update(ctx, id, &model.DriverUpdateParams{Name: strPtr("Ah Tan Jr")})
+	if err != nil {
+		t.Fatalf("Update name: %v", err)
+	}
+	if !reflect.DeepEqual(d.Skills, []string{"forklift", "cold chain"}) {
+		t.Errorf("skills after name update = %q, want them kept", d.Skills)
+	}
+
+	// An empty list clears them
+	d, err = svc.Update(ctx, id, &model.DriverUpdateParams{SkillList: &[]string{}})
+	if err != nil {
+		t.Fatalf("Update skills: %v", err)
+	}
+	if len(d.Skills) != 0 {
+		t.Errorf("skills after clearing = %q, want none", d.Skills)
+	}
+}
+
+func strPtr(s string) *string { return &s }
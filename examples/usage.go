@@ -86,11 +86,11 @@ func main() {
 	// 6. Use Drivers Service
 	if runDriver {
 		fmt.Println("\nListing Drivers:")
-		drvOpt := model.ListOptions{PerPage: 5}
+		drvOpt := model.DriverListOptions{CommonListOptions: model.CommonListOptions{ListOptions: model.ListOptions{PerPage: 5}}}
 		driverIter := driversService.List(ctx, &drvOpt)
 		for driverIter.Next() {
 			drv := driverIter.Value()
-			fmt.Printf("- Driver %s (Phone: %s)\n", drv.Name, drv.Phone)
+			fmt.Printf("- Driver %s (Phone: %s)\n", drv.Name, drv.ContactNumber)
 			break
 		}
 	}
@@ -0,0 +1,339 @@
+// Package importer creates jobs in bulk from CSV files.
+//
+// Each row is mapped onto a model.JobParams by a declarative Spec, validated
+// locally, and submitted concurrently through a jobs.Service (and therefore
+// through the client's rate limiter). The outcome of every row is collected
+// in a Report that can be written back out as CSV.
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/model"
+)
+
+// Creator creates a job. *jobs.Service satisfies it.
+type Creator interface {
+	Create(ctx context.Context, job *model.JobParams) (*model.Job, error)
+}
+
+// Importer maps CSV rows to jobs and creates them
+type Importer struct {
+	creator Creator
+	spec    Spec
+
+	// Concurrency is the number of rows submitted at once (default 4).
+	// Requests still wait on the client's rate limiter.
+	Concurrency int
+}
+
+// New creates an importer. The spec is checked up front so mapping
+// mistakes fail before any job is created.
+func New(creator Creator, spec Spec) (*Importer, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &Importer{creator: creator, spec: spec, Concurrency: 4}, nil
+}
+
+// FieldError is a validation problem with a single cell or with the mapped job
+type FieldError struct {
+	Column  string `json:"column,omitempty"` // CSV header, empty for job-level checks
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	if e.Column != "" {
+		return fmt.Sprintf("%s: %s", e.Column, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// RowError is returned for a row that failed validation. It lists every problem found in the row.
+type RowError struct {
+	Line   int
+	Fields []*FieldError
+}
+
+func (e *RowError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, strings.Join(msgs, "; "))
+}
+
+// Result is the outcome of a single row
+type Result struct {
+	Line   int      // 1-based line the row starts on in the CSV, counting the header
+	Record []string // The original cells
+	Params *model.JobParams
+	JobID  int   // Set when the job was created
+	Err    error // *RowError for validation failures, otherwise the API error (often a *client.APIError)
+}
+
+// Report is the outcome of an import, one result per data row in input order
+type Report struct {
+	Header  []string
+	Results []Result
+}
+
+// Created returns the number of rows that produced a job
+func (r *Report) Created() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Err == nil && res.JobID != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns the number of rows that did not produce a job
+func (r *Report) Failed() int {
+	return len(r.Results) - r.Created()
+}
+
+// WriteCSV writes the original rows with "status", "job_id" and "error" columns appended
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := append(append([]string{}, r.Header...), "status", "job_id", "error")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, res := range r.Results {
+		status, jobID, msg := "created", fmt.Sprint(res.JobID), ""
+		if res.Err != nil {
+			status, jobID, msg = "failed", "", res.Err.Error()
+		} else if res.JobID == 0 {
+			status, jobID = "skipped", ""
+		}
+		row := append(append([]string{}, res.Record...), status, jobID, msg)
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Parse reads and validates every row without creating anything.
+// Rows that fail validation have a *RowError in Err.
+func (im *Importer) Parse(r io.Reader) (*Report, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("importer: failed to read header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff") // Excel BOM
+	}
+
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		index[strings.TrimSpace(h)] = i
+	}
+	for _, col := range im.spec.Columns {
+		if _, ok := index[col.Header]; col.Header != "" && !ok && col.Required && col.Default == "" {
+			return nil, fmt.Errorf("importer: required column %q is missing", col.Header)
+		}
+	}
+
+	report := &Report{Header: header}
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report.Results = append(report.Results, Result{Line: parseErr.StartLine, Record: record, Err: err})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("importer: failed to read CSV: %w", err)
+		}
+		// Quoted fields can span lines, so ask the reader where the row started
+		line, _ := cr.FieldPos(0)
+		params, rowErr := im.mapRow(line, record, index)
+		res := Result{Line: line, Record: record, Params: params}
+		if rowErr != nil {
+			res.Err = rowErr
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report, nil
+}
+
+// Import parses the CSV and creates a job for every valid row.
+// The returned error is only for problems with the file as a whole; row failures are in the report.
+func (im *Importer) Import(ctx context.Context, r io.Reader) (*Report, error) {
+	report, err := im.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := im.Concurrency
+	if workers <= 0 {
+		workers = 1
+	}
+
+	rows := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rows {
+				res := &report.Results[i]
+				if err := ctx.Err(); err != nil {
+					res.Err = err
+					continue
+				}
+				job, err := im.creator.Create(ctx, res.Params)
+				if err != nil {
+					res.Err = err
+					continue
+				}
+				res.JobID = job.ID
+			}
+		}()
+	}
+
+	for i := range report.Results {
+		if report.Results[i].Err == nil {
+			rows <- i
+		}
+	}
+	close(rows)
+	wg.Wait()
+
+	return report, nil
+}
+
+func (im *Importer) mapRow(line int, record []string, index map[string]int) (*model.JobParams, error) {
+	params := &model.JobParams{}
+	rowErr := &RowError{Line: line}
+
+	for _, col := range im.spec.Columns {
+		raw := ""
+		if i, ok := index[col.Header]; ok && col.Header != "" && i < len(record) {
+			raw = strings.TrimSpace(record[i])
+		}
+		if raw == "" {
+			raw = col.Default
+		}
+		if raw == "" {
+			if col.Required {
+				rowErr.Fields = append(rowErr.Fields, &FieldError{Column: col.Header, Field: col.Field, Message: "is required"})
+			}
+			continue
+		}
+		if err := setPath(reflect.ValueOf(params).Elem(), strings.Split(col.Field, "."), raw, col); err != nil {
+			rowErr.Fields = append(rowErr.Fields, &FieldError{Column: col.Header, Field: col.Field, Message: err.Error()})
+		}
+	}
+
+	// Job-level checks, skipping fields that already failed at the cell level
+	failed := make(map[string]bool, len(rowErr.Fields))
+	for _, f := range rowErr.Fields {
+		failed[f.Field] = true
+	}
+	for _, f := range validateJob(params) {
+		if !failed[f.Field] {
+			rowErr.Fields = append(rowErr.Fields, f)
+		}
+	}
+	if len(rowErr.Fields) > 0 {
+		return params, rowErr
+	}
+	return params, nil
+}
+
+// timeLayouts are the formats accepted for time_from / time_to
+var timeLayouts = []string{"2006-01-02 15:04:05", time.RFC3339, "2006-01-02 15:04", "2006-01-02"}
+
+// validateJob applies the checks the API would otherwise reject the row for
+func validateJob(p *model.JobParams) []*FieldError {
+	var errs []*FieldError
+	if p.JobType == "" {
+		errs = append(errs, &FieldError{Field: "job_type", Message: "is required"})
+	}
+	if p.CustomerID == 0 {
+		errs = append(errs, &FieldError{Field: "customer_id", Message: "is required"})
+	}
+	if len(p.TasksAttributes) == 0 {
+		errs = append(errs, &FieldError{Field: "tasks_attributes", Message: "at least one task is required"})
+	}
+
+	if bt := p.BaseTaskAttributes; bt != nil {
+		var timeType model.TimeType
+		if bt.TimeType != nil {
+			timeType = *bt.TimeType
+		}
+		errs = append(errs, validateWindow("base_task_attributes", bt.TimeFrom, bt.TimeTo, timeType)...)
+	}
+	for i, t := range p.TasksAttributes {
+		prefix := fmt.Sprintf("tasks_attributes.%d", i)
+		errs = append(errs, validateWindow(prefix, t.TimeFrom, t.TimeTo, t.TimeType)...)
+		if t.AddressAttributes == nil || t.AddressAttributes.Line1 == "" {
+			errs = append(errs, &FieldError{Field: prefix + ".address_attributes.line_1", Message: "is required"})
+		}
+		for j, m := range t.Measurements {
+			if m.Quantity < 0 || m.Weight < 0 || m.Volume < 0 {
+				errs = append(errs, &FieldError{Field: fmt.Sprintf("%s.measurements_attributes.%d", prefix, j), Message: "must not be negative"})
+			}
+		}
+	}
+	return errs
+}
+
+func validateWindow(prefix string, from, to *string, timeType model.TimeType) []*FieldError {
+	var errs []*FieldError
+	switch timeType {
+	case "", model.TimeTypeAM, model.TimeTypePM, model.TimeTypeAllDay, model.TimeTypeCustom:
+	default:
+		errs = append(errs, &FieldError{Field: prefix + ".time_type", Message: fmt.Sprintf("unknown time type %q", timeType)})
+	}
+
+	var fromT, toT time.Time
+	var ok bool
+	if from != nil {
+		if fromT, ok = parseTime(*from); !ok {
+			errs = append(errs, &FieldError{Field: prefix + ".time_from", Message: fmt.Sprintf("%q is not a valid time", *from)})
+		}
+	}
+	if to != nil {
+		if toT, ok = parseTime(*to); !ok {
+			errs = append(errs, &FieldError{Field: prefix + ".time_to", Message: fmt.Sprintf("%q is not a valid time", *to)})
+		}
+	}
+	if !fromT.IsZero() && !toT.IsZero() && toT.Before(fromT) {
+		errs = append(errs, &FieldError{Field: prefix + ".time_to", Message: "is before time_from"})
+	}
+	if timeType == model.TimeTypeCustom && (from == nil || to == nil) {
+		errs = append(errs, &FieldError{Field: prefix + ".time_type", Message: "custom time type needs time_from and time_to"})
+	}
+	return errs
+}
+
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
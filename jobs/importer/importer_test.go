@@ -0,0 +1,310 @@
+package importer_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Willias7788/go-versafleet-sdk/jobs/importer"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+)
+
+// spec maps testdata/jobs.csv, with a second task for the collection address
+var spec = importer.Spec{Columns: []importer.Column{
+	{Header: "job_type", Field: "job_type", Default: "delivery"},
+	{Header: "customer_id", Field: "customer_id", Required: true},
+	{Header: "tracking_id", Field: "tasks_attributes.0.tracking_id", Required: true},
+	{Header: "address", Field: "tasks_attributes.0.address_attributes.line_1"},
+	{Header: "tags", Field: "tasks_attributes.0.tag_list", Separator: ";"},
+	{Header: "time_from", Field: "tasks_attributes.0.time_from"},
+	{Header: "time_to", Field: "tasks_attributes.0.time_to"},
+	{Header: "quantity", Field: "tasks_attributes.0.measurements_attributes.0.quantity"},
+	{Header: "collect_address", Field: "tasks_attributes.1.address_attributes.line_1"},
+	{Header: "door_code", Field: "tasks_attributes.0.custom_fields_attributes", CustomFieldID: 11},
+	{Field: "remarks", Default: "imported"},
+}}
+
+// fakeCreator hands out job IDs, failing for the customers in fail
+type fakeCreator struct {
+	fail map[int]bool
+
+	mu      sync.Mutex
+	nextID  int
+	created []*model.JobParams
+}
+
+func (f *fakeCreator) Create(_ context.Context, job *model.JobParams) (*model.Job, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail[job.CustomerID] {
+		return nil, errors.New("customer is suspended")
+	}
+	f.nextID++
+	f.created = append(f.created, job)
+	return &model.Job{ID: f.nextID}, nil
+}
+
+func newImporter(t *testing.T, creator importer.Creator) *importer.Importer {
+	t.Helper()
+	im, err := importer.New(creator, spec)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return im
+}
+
+func parseFile(t *testing.T, im *importer.Importer, name string) *importer.Report {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	report, err := im.Parse(f)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return report
+}
+
+func TestParseMapsColumns(t *testing.T) {
+	report := parseFile(t, newImporter(t, &fakeCreator{}), "jobs.csv")
+	if len(report.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(report.Results))
+	}
+
+	descID := 11
+	want := []*model.JobParams{
+		{
+			JobType:    "delivery",
+			CustomerID: 7,
+			Remarks:    "imported",
+			TasksAttributes: []model.TaskParams{
+				{
+					TrackingID:        "TRK-1",
+					AddressAttributes: &model.Address{Line1: "1 Main St"},
+					TagList:           []string{"fragile", "cold"},
+					TimeFrom:          strPtr("2024-03-01 09:00:00"),
+					TimeTo:            strPtr("2024-03-01 12:00:00"),
+					Measurements:      []model.MeasurementParams{{Quantity: 3}},
+					CustomFields:      []model.CustomField{{CustomFieldDescriptionID: &descID, Value: "1234"}},
+				},
+				{AddressAttributes: &model.Address{Line1: "9 Depot Rd"}},
+			},
+		},
+		{
+			// Empty job_type falls back to the default, and empty cells leave fields unset
+			JobType:    "delivery",
+			CustomerID: 8,
+			Remarks:    "imported",
+			TasksAttributes: []model.TaskParams{
+				{TrackingID: "TRK-2", AddressAttributes: &model.Address{Line1: "2 Main St"}},
+			},
+		},
+	}
+	for i, res := range report.Results {
+		if res.Err != nil {
+			t.Errorf("line %d: %v", res.Line, res.Err)
+		}
+		if res.Line != i+2 {
+			t.Errorf("result %d is line %d, want %d", i, res.Line, i+2)
+		}
+		if !reflect.DeepEqual(res.Params, want[i]) {
+			t.Errorf("line %d params =\n%+v\nwant\n%+v", res.Line, res.Params, want[i])
+		}
+	}
+}
+
+func TestParseReportsRowErrors(t *testing.T) {
+	report := parseFile(t, newImporter(t, &fakeCreator{}), "invalid.csv")
+
+	tests := []struct {
+		line   int
+		fields []importer.FieldError // nil for a valid row
+	}{
+		{2, []importer.FieldError{{Column: "customer_id", Field: "customer_id", Message: `"abc" is not a whole number`}}},
+		{3, []importer.FieldError{{Column: "tracking_id", Field: "tasks_attributes.0.tracking_id", Message: "is required"}}},
+		{4, []importer.FieldError{
+			{Field: "tasks_attributes.0.time_to", Message: "is before time_from"},
+			{Field: "tasks_attributes.0.address_attributes.line_1", Message: "is required"},
+			{Field: "tasks_attributes.0.measurements_attributes.0", Message: "must not be negative"},
+		}},
+		{5, nil},
+	}
+	if len(report.Results) != len(tests) {
+		t.Fatalf("got %d results, want %d", len(report.Results), len(tests))
+	}
+	for i, tt := range tests {
+		res := report.Results[i]
+		if res.Line != tt.line {
+			t.Errorf("result %d is line %d, want %d", i, res.Line, tt.line)
+		}
+		if tt.fields == nil {
+			if res.Err != nil {
+				t.Errorf("line %d: unexpected error %v", tt.line, res.Err)
+			}
+			continue
+		}
+		var rowErr *importer.RowError
+		if !errors.As(res.Err, &rowErr) {
+			t.Errorf("line %d: error %v is not a *RowError", tt.line, res.Err)
+			continue
+		}
+		if rowErr.Line != tt.line {
+			t.Errorf("RowError.Line = %d, want %d", rowErr.Line, tt.line)
+		}
+		got := make([]importer.FieldError, len(rowErr.Fields))
+		for j, f := range rowErr.Fields {
+			got[j] = *f
+		}
+		if !reflect.DeepEqual(got, tt.fields) {
+			t.Errorf("line %d fields =\n%+v\nwant\n%+v", tt.line, got, tt.fields)
+		}
+	}
+}
+
+func TestParseMissingRequiredColumn(t *testing.T) {
+	f, err := os.Open("testdata/missing_column.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	_, err = newImporter(t, &fakeCreator{}).Parse(f)
+	if err == nil || !strings.Contains(err.Error(), `"customer_id"`) {
+		t.Errorf("Parse = %v, want a missing customer_id column error", err)
+	}
+}
+
+func TestParseLinesWithMultiLineFields(t *testing.T) {
+	input := "job_type,customer_id,tracking_id,address\n" +
+		"delivery,7,TRK-1,\"1 Main St\nUnit 5\n#02-01\"\n" + // lines 2-4
+		"delivery,abc,TRK-2,2 Main St\n" + // line 5
+		"delivery,7,\"TRK-\"3\",3 Main St\n" + // line 6, a bare quote
+		"delivery,7,,4 Main St\n" // line 7
+	report, err := newImporter(t, &fakeCreator{}).Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := []int{2, 5, 6, 7}
+	if len(report.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(report.Results), len(want))
+	}
+	for i, line := range want {
+		if got := report.Results[i].Line; got != line {
+			t.Errorf("result %d is line %d, want %d", i, got, line)
+		}
+	}
+	if addr := report.Results[0].Params.TasksAttributes[0].AddressAttributes.Line1; addr != "1 Main St\nUnit 5\n#02-01" {
+		t.Errorf("multi-line address = %q", addr)
+	}
+	if err := report.Results[1].Err; err == nil || !strings.HasPrefix(err.Error(), "line 5:") {
+		t.Errorf("line 5 error = %v", err)
+	}
+	var parseErr *csv.ParseError
+	if !errors.As(report.Results[2].Err, &parseErr) {
+		t.Errorf("line 6 error = %v, want a *csv.ParseError", report.Results[2].Err)
+	}
+	if err := report.Results[3].Err; err == nil || !strings.HasPrefix(err.Error(), "line 7:") {
+		t.Errorf("line 7 error = %v", err)
+	}
+}
+
+func TestRequiredColumnWithDefault(t *testing.T) {
+	s := importer.Spec{Columns: append([]importer.Column{}, spec.Columns...)}
+	s.Columns[1].Default = "42" // customer_id, which missing_column.csv lacks
+	im, err := importer.New(&fakeCreator{}, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open("testdata/missing_column.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	report, err := im.Parse(f)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if res := report.Results[0]; res.Err != nil || res.Params.CustomerID != 42 {
+		t.Errorf("line 2: customer %d, error %v; want the default 42", res.Params.CustomerID, res.Err)
+	}
+}
+
+func TestImportReport(t *testing.T) {
+	creator := &fakeCreator{fail: map[int]bool{8: true}}
+	im := newImporter(t, creator)
+
+	input, err := os.ReadFile("testdata/invalid.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Line 6 is valid but rejected by the creator
+	input = append(input, "delivery,8,TRK-5,5 Main St,,,\n"...)
+
+	report, err := im.Import(context.Background(), bytes.NewReader(input))
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if report.Created() != 1 || report.Failed() != 4 {
+		t.Errorf("created %d, failed %d; want 1 and 4", report.Created(), report.Failed())
+	}
+	if len(creator.created) != 1 || creator.created[0].TasksAttributes[0].TrackingID != "TRK-4" {
+		t.Errorf("creator got %d jobs, want only TRK-4", len(creator.created))
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteCSV(&out); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"status", "job_id", "error"},
+		{"failed", "", `line 2: customer_id: "abc" is not a whole number`},
+		{"failed", "", "line 3: tracking_id: is required"},
+		{"failed", "", "line 4: tasks_attributes.0.time_to: is before time_from; tasks_attributes.0.address_attributes.line_1: is required; tasks_attributes.0.measurements_attributes.0: must not be negative"},
+		{"created", "1", ""},
+		{"failed", "", "customer is suspended"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d CSV rows, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		if got := row[len(row)-3:]; !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("row %d ends %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		col  importer.Column
+		want string
+	}{
+		{"unknown field", importer.Column{Header: "x", Field: "tasks_attributes.0.colour"}, `unknown field "colour"`},
+		{"missing index", importer.Column{Header: "x", Field: "tasks_attributes.tracking_id"}, "expected a list index"},
+		{"struct field", importer.Column{Header: "x", Field: "tasks_attributes.0.address_attributes"}, "cannot be set from CSV"},
+		{"custom field target", importer.Column{Header: "x", Field: "remarks", CustomFieldID: 1}, "custom_fields_attributes"},
+		{"no header or default", importer.Column{Field: "remarks"}, "needs a header or a default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := importer.Spec{Columns: []importer.Column{tt.col}}.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string { return &s }
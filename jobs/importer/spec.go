@@ -0,0 +1,196 @@
+package importer
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Willias7788/go-versafleet-sdk/model"
+)
+
+// Column maps one CSV column onto a field of model.JobParams.
+//
+// Field is a dotted path of JSON names starting at model.JobParams, with numeric
+// segments indexing into slices, for example:
+//
+//	job_type
+//	base_task_attributes.address_attributes.line_1
+//	tasks_attributes.0.time_from
+//	tasks_attributes.0.measurements_attributes.0.quantity
+//	tasks_attributes.0.tag_list
+type Column struct {
+	Header   string // CSV header. May be empty for a constant column that only uses Default.
+	Field    string
+	Required bool
+	Default  string // Used when the column is missing or the cell is empty
+
+	// Separator splits the cell for []string fields such as tag_list (default ",")
+	Separator string
+
+	// CustomFieldID turns the cell into a model.CustomField with this description ID,
+	// appended to the custom_fields_attributes slice that Field points at.
+	CustomFieldID int
+}
+
+// Spec is the declarative mapping from CSV columns to a job
+type Spec struct {
+	Columns []Column
+}
+
+var (
+	jobParamsType   = reflect.TypeOf(model.JobParams{})
+	customFieldType = reflect.TypeOf(model.CustomField{})
+)
+
+// Validate checks that every column points at a field that exists and can be set from text
+func (s Spec) Validate() error {
+	if len(s.Columns) == 0 {
+		return fmt.Errorf("importer: spec has no columns")
+	}
+	for _, col := range s.Columns {
+		if col.Header == "" && col.Default == "" {
+			return fmt.Errorf("importer: column for %q needs a header or a default", col.Field)
+		}
+		if err := checkPath(jobParamsType, strings.Split(col.Field, "."), col); err != nil {
+			return fmt.Errorf("importer: column %q: %w", col.Header, err)
+		}
+	}
+	return nil
+}
+
+// checkPath walks a field path over a type without a value, so spec errors surface before any row is read
+func checkPath(t reflect.Type, path []string, col Column) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if len(path) == 0 {
+		if col.CustomFieldID != 0 {
+			if t.Kind() != reflect.Slice || t.Elem() != customFieldType {
+				return fmt.Errorf("custom field column must point at a custom_fields_attributes list")
+			}
+			return nil
+		}
+		if !isSettable(t) {
+			return fmt.Errorf("field of type %s cannot be set from CSV", t)
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		f, ok := fieldByJSONName(t, path[0])
+		if !ok {
+			return fmt.Errorf("unknown field %q", path[0])
+		}
+		return checkPath(f.Type, path[1:], col)
+	case reflect.Slice:
+		if _, err := strconv.Atoi(path[0]); err != nil {
+			return fmt.Errorf("expected a list index, got %q", path[0])
+		}
+		return checkPath(t.Elem(), path[1:], col)
+	}
+	return fmt.Errorf("cannot descend into %s at %q", t, path[0])
+}
+
+func isSettable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			if inner, ok := fieldByJSONName(f.Type, name); ok {
+				inner.Index = append([]int{i}, inner.Index...)
+				return inner, true
+			}
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// setPath assigns raw to the field at path, allocating pointers and growing slices on the way
+func setPath(v reflect.Value, path []string, raw string, col Column) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if len(path) == 0 {
+		return setValue(v, raw, col)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		f, _ := fieldByJSONName(v.Type(), path[0])
+		return setPath(v.FieldByIndex(f.Index), path[1:], raw, col)
+	case reflect.Slice:
+		idx, _ := strconv.Atoi(path[0])
+		if idx >= v.Len() {
+			grown := reflect.MakeSlice(v.Type(), idx+1, idx+1)
+			reflect.Copy(grown, v)
+			v.Set(grown)
+		}
+		return setPath(v.Index(idx), path[1:], raw, col)
+	}
+	return fmt.Errorf("cannot descend into %s", v.Type())
+}
+
+func setValue(v reflect.Value, raw string, col Column) error {
+	if col.CustomFieldID != 0 {
+		id := col.CustomFieldID
+		v.Set(reflect.Append(v, reflect.ValueOf(model.CustomField{CustomFieldDescriptionID: &id, Value: raw})))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", raw)
+		}
+		v.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		sep := col.Separator
+		if sep == "" {
+			sep = ","
+		}
+		for _, part := range strings.Split(raw, sep) {
+			if part = strings.TrimSpace(part); part != "" {
+				v.Set(reflect.Append(v, reflect.ValueOf(part).Convert(v.Type().Elem())))
+			}
+		}
+	default:
+		return fmt.Errorf("cannot set %s from CSV", v.Type())
+	}
+	return nil
+}
@@ -2,7 +2,12 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/Willias7788/go-versafleet-sdk/client"
 	"github.com/Willias7788/go-versafleet-sdk/model"
@@ -23,23 +28,26 @@ type JobListResponse struct {
 
 // List returns an iterator to list all jobs
 func (s *Service) List(ctx context.Context, opts *model.JobListOptions) *client.Iterator[model.Job, *model.JobListOptions] {
-	return client.NewIterator(ctx, s.client, "/v2/jobs", opts, func(ctx context.Context, path string, opts *model.JobListOptions) ([]model.Job, *model.Meta, error) {
-		var resp JobListResponse
-		// Construct query params manually or use library (resty supports SetQueryParam)
-		// Simulating simplistic approach here.
-		// Detailed implementation would map opts to query params.
-
-		path = fmt.Sprintf("%s?page=%d&per_page=%d", path, opts.Page, opts.PerPage)
-		fmt.Println("List Job Path:", path)
-		if err := s.client.Get(ctx, path, &resp); err != nil {
-			return nil, nil, err
-		}
-		return resp.Jobs, resp.Meta, nil
-	})
+	return client.NewIterator(ctx, s.client, "/v2/jobs", opts, s.listPage)
+}
+
+// ResumeList continues listing jobs from a checkpoint saved by an earlier iterator
+func (s *Service) ResumeList(ctx context.Context, cp *client.Checkpoint) (*client.Iterator[model.Job, *model.JobListOptions], error) {
+	return client.ResumeIterator(ctx, s.client, cp, s.listPage)
+}
+
+func (s *Service) listPage(ctx context.Context, path string, opts *model.JobListOptions) ([]model.Job, *model.Meta, error) {
+	ctx = client.WithOperation(ctx, "jobs.List")
+	var resp JobListResponse
+	if err := s.client.GetWithQuery(ctx, path, opts, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Jobs, resp.Meta, nil
 }
 
 // Get retrieves a single job by ID
 func (s *Service) Get(ctx context.Context, id string) (*model.Job, error) {
+	ctx = client.WithOperation(ctx, "jobs.Get")
 	var job model.Job
 	path := fmt.Sprintf("/v2/jobs/%s", id)
 	err := s.client.Get(ctx, path, &job)
@@ -49,8 +57,112 @@ func (s *Service) Get(ctx context.Context, id string) (*model.Job, error) {
 	return &job, nil
 }
 
-// Create creates a new job
+// Create creates a new job. The request carries the context's idempotency key (see
+// client.WithIdempotencyKey), or a generated one if a client.IdempotencyStore is set.
+// Without a store, a failed Create is retried only if it carries the caller's key.
+//
+// With a client.IdempotencyStore configured, a repeated Create with the same key returns the
+// job created the first time. If the first attempt's outcome was unknown (e.g. it timed out),
+// the job is looked up by its tasks' tracking IDs or invoice numbers, so set one of them.
+// Concurrent Creates with the same key on one client wait for the first to finish.
 func (s *Service) Create(ctx context.Context, job *model.JobParams) (*model.Job, error) {
+	ctx = client.WithOperation(ctx, "jobs.Create")
+	store := s.client.IdempotencyStore()
+	if store == nil {
+		// Only a key from the caller is sent, since a keyed POST is retried on the
+		// assumption that the API deduplicates it
+		return s.create(ctx, job)
+	}
+	ctx, key := client.EnsureIdempotencyKey(ctx)
+
+	release, err := s.reserve(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rec, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("versafleet-sdk: idempotency store failed: %w", err)
+	}
+	if rec != nil {
+		existing, err := s.findCreated(ctx, rec, job)
+		if err != nil || existing != nil {
+			if existing != nil {
+				s.client.Logger().InfoContext(ctx, "versafleet-sdk: job already created with this idempotency key",
+					slog.String("idempotency_key", key), slog.Int("job_id", existing.ID))
+			}
+			return existing, err
+		}
+	} else if err := store.Put(ctx, &client.IdempotencyRecord{Key: key, CreatedAt: time.Now()}); err != nil {
+		return nil, fmt.Errorf("versafleet-sdk: idempotency store failed: %w", err)
+	}
+
+	created, err := s.create(ctx, job)
+	if err != nil {
+		if !outcomeUnknown(err) {
+			// Definitely not created, so the key is free for a fresh attempt
+			_ = store.Delete(ctx, key)
+			return nil, err
+		}
+		// The job may exist anyway; if it can be found, the create succeeded
+		if found, findErr := s.findByTasks(ctx, job); findErr == nil && found != nil {
+			s.client.Logger().InfoContext(ctx, "versafleet-sdk: create failed but the job exists",
+				slog.String("idempotency_key", key), slog.Int("job_id", found.ID), slog.Any("error", err))
+			created = found
+		} else {
+			return nil, err
+		}
+	}
+	if err := store.Put(ctx, &client.IdempotencyRecord{Key: key, ID: created.ID, CreatedAt: time.Now()}); err != nil {
+		s.client.Logger().WarnContext(ctx, "versafleet-sdk: failed to record created job in idempotency store",
+			slog.String("idempotency_key", key), slog.Int("job_id", created.ID), slog.Any("error", err))
+	}
+	return created, nil
+}
+
+// inFlight holds the keyed Creates in progress, so a second Create with the same key waits
+// for the first to record its job instead of missing it in the store and posting again
+var inFlight = struct {
+	sync.Mutex
+	creates map[inFlightKey]chan struct{}
+}{creates: make(map[inFlightKey]chan struct{})}
+
+// inFlightKey scopes keys to a client, as each client has its own store
+type inFlightKey struct {
+	client *client.Client
+	key    string
+}
+
+// reserve waits until no other Create with key is in flight on the client, then marks key
+// as in flight until release is called
+func (s *Service) reserve(ctx context.Context, key string) (release func(), err error) {
+	k := inFlightKey{client: s.client, key: key}
+	for {
+		inFlight.Lock()
+		done, busy := inFlight.creates[k]
+		if !busy {
+			done = make(chan struct{})
+			inFlight.creates[k] = done
+			inFlight.Unlock()
+			return func() {
+				inFlight.Lock()
+				delete(inFlight.creates, k)
+				inFlight.Unlock()
+				close(done)
+			}, nil
+		}
+		inFlight.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (s *Service) create(ctx context.Context, job *model.JobParams) (*model.Job, error) {
 	var createdJob model.JobResponse
 	err := s.client.Post(ctx, "/v2/jobs", job, &createdJob)
 	if err != nil {
@@ -59,7 +171,67 @@ func (s *Service) Create(ctx context.Context, job *model.JobParams) (*model.Job,
 	return &createdJob.Job, nil
 }
 
+// findCreated returns the job recorded for an earlier Create with the same key, or nil if there was none
+func (s *Service) findCreated(ctx context.Context, rec *client.IdempotencyRecord, job *model.JobParams) (*model.Job, error) {
+	if rec.ID != 0 {
+		return s.Get(ctx, strconv.Itoa(rec.ID))
+	}
+	found, err := s.findByTasks(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("versafleet-sdk: failed to look up job of earlier create: %w", err)
+	}
+	if found != nil {
+		_ = s.client.IdempotencyStore().Put(ctx, &client.IdempotencyRecord{Key: rec.Key, ID: found.ID, CreatedAt: rec.CreatedAt})
+	}
+	return found, nil
+}
+
+// findByTasks looks for an existing job with a task matching one of job's tracking IDs or invoice numbers
+func (s *Service) findByTasks(ctx context.Context, job *model.JobParams) (*model.Job, error) {
+	for _, t := range job.TasksAttributes {
+		var opts model.TaskListOptions
+		switch {
+		case t.TrackingID != "":
+			opts.TrackingID = &t.TrackingID
+		case t.InvoiceNumber != "":
+			opts.Keyword = &t.InvoiceNumber
+		default:
+			continue
+		}
+
+		var resp struct {
+			Tasks []model.Task `json:"tasks"`
+		}
+		if err := s.client.GetWithQuery(ctx, "/tasks", &opts, &resp); err != nil {
+			return nil, err
+		}
+		for _, found := range resp.Tasks {
+			if found.JobID == 0 {
+				continue
+			}
+			if (t.TrackingID != "" && found.TrackingID == t.TrackingID) ||
+				(t.TrackingID == "" && found.InvoiceNumber == t.InvoiceNumber) {
+				return s.Get(ctx, strconv.Itoa(found.JobID))
+			}
+		}
+	}
+	return nil, nil
+}
+
+// outcomeUnknown reports whether a failed create may still have created the job:
+// network errors and 5xx, as opposed to 4xx rejections
+func outcomeUnknown(err error) bool {
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+// Update updates a job. The request carries the context's idempotency key, or a generated one.
 func (s *Service) Update(ctx context.Context, jobId string, job *model.JobUpdateParams) (*model.Job, error) {
+	ctx = client.WithOperation(ctx, "jobs.Update")
+	ctx, _ = client.EnsureIdempotencyKey(ctx)
 	var updatedJob model.JobResponse
 	err := s.client.Put(ctx, fmt.Sprintf("/v2/jobs/%s", jobId), job, &updatedJob)
 	if err != nil {
@@ -69,6 +241,7 @@ func (s *Service) Update(ctx context.Context, jobId string, job *model.JobUpdate
 }
 
 func (s *Service) Delete(ctx context.Context, id string) error {
+	ctx = client.WithOperation(ctx, "jobs.Delete")
 	path := fmt.Sprintf("/v2/jobs/%s", id)
 	err := s.client.Delete(ctx, path)
 	if err != nil {
@@ -0,0 +1,185 @@
+package jobs_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/jobs"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+	"github.com/Willias7788/go-versafleet-sdk/versafleettest"
+)
+
+func TestListFiltersReachTheWire(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	svc := jobs.New(client.New(srv.Config(), client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100}))))
+
+	tests := []struct {
+		name string
+		opts *model.JobListOptions
+		want url.Values
+	}{
+		{"customer", &model.JobListOptions{CustomerID: intPtr(7)}, url.Values{"customer_id": {"7"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := svc.List(context.Background(), tt.opts)
+			defer it.Close()
+			it.Next()
+			if err := it.Err(); err != nil {
+				t.Fatalf("List: %v", err)
+			}
+
+			got := lastQuery(t, srv, "/v2/jobs")
+			for key, want := range tt.want {
+				if !reflect.DeepEqual(got[key], want) {
+					t.Errorf("%s = %q, want %q (query %s)", key, got[key], want, got.Encode())
+				}
+			}
+		})
+	}
+}
+
+func TestCreateRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       string // Supplied by the caller
+		opts      []client.Option
+		wantPosts int
+	}{
+		// Without a key the API can't deduplicate, so a retry could create the job twice
+		{"default client", "", nil, 1},
+		{"caller's key", "order-1234", []client.Option{client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 4, MinWait: time.Millisecond, MaxWait: 5 * time.Millisecond})}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := versafleettest.NewServer()
+			defer srv.Close()
+			srv.InjectFault(versafleettest.Fault{Status: http.StatusServiceUnavailable, Count: 1, Method: http.MethodPost, PathPrefix: "/v2/jobs"})
+			opts := append([]client.Option{client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100}))}, tt.opts...)
+			svc := jobs.New(client.New(srv.Config(), opts...))
+
+			ctx := context.Background()
+			if tt.key != "" {
+				ctx = client.WithIdempotencyKey(ctx, tt.key)
+			}
+			params := &model.JobParams{JobType: "delivery", CustomerID: 1, TasksAttributes: []model.TaskParams{{TrackingID: "TRK-1"}}}
+			_, err := svc.Create(ctx, params)
+			if (err == nil) != (tt.wantPosts > 1) {
+				t.Errorf("Create err = %v", err)
+			}
+
+			posts := 0
+			for _, r := range srv.Requests() {
+				if r.Method == http.MethodPost && r.URL.Path == "/v2/jobs" {
+					posts++
+					if got := r.Header.Get(client.IdempotencyKeyHeader); got != tt.key {
+						t.Errorf("Idempotency-Key = %q, want %q", got, tt.key)
+					}
+				}
+			}
+			if posts != tt.wantPosts {
+				t.Errorf("%d jobs posted, want %d", posts, tt.wantPosts)
+			}
+		})
+	}
+}
+
+func TestConcurrentCreateSameKey(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	// Slow responses make the Creates overlap
+	srv.SetLatency(50 * time.Millisecond)
+	c := client.New(srv.Config(),
+		client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100})),
+		client.WithIdempotencyStore(client.NewMemoryIdempotencyStore(time.Hour)),
+	)
+	ctx := client.WithIdempotencyKey(context.Background(), "import-row-1")
+	params := &model.JobParams{JobType: "delivery", CustomerID: 1, TasksAttributes: []model.TaskParams{{TrackingID: "TRK-1"}}}
+
+	const callers = 5
+	ids := make([]int, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Separate services on one client share the reservation
+			job, err := jobs.New(c).Create(ctx, params)
+			errs[i] = err
+			if job != nil {
+				ids[i] = job.ID
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Create %d: %v", i, err)
+		}
+		if ids[i] != ids[0] {
+			t.Errorf("Create %d returned job %d, want %d", i, ids[i], ids[0])
+		}
+	}
+	posts := 0
+	for _, r := range srv.Requests() {
+		if r.Method == http.MethodPost && r.URL.Path == "/v2/jobs" {
+			posts++
+		}
+	}
+	if posts != 1 {
+		t.Errorf("%d jobs posted, want 1", posts)
+	}
+}
+
+func TestCreateReservationRespectsContext(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	srv.SetLatency(200 * time.Millisecond)
+	c := client.New(srv.Config(),
+		client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100})),
+		client.WithIdempotencyStore(client.NewMemoryIdempotencyStore(time.Hour)),
+	)
+	ctx := client.WithIdempotencyKey(context.Background(), "import-row-1")
+	params := &model.JobParams{JobType: "delivery", CustomerID: 1, TasksAttributes: []model.TaskParams{{TrackingID: "TRK-1"}}}
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := jobs.New(c).Create(ctx, params)
+		first <- err
+	}()
+	time.Sleep(20 * time.Millisecond) // Let the first Create take the key
+
+	waitCtx, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+	defer cancel()
+	if _, err := jobs.New(c).Create(waitCtx, params); err != context.DeadlineExceeded {
+		t.Errorf("waiting Create = %v, want deadline exceeded", err)
+	}
+	if err := <-first; err != nil {
+		t.Errorf("first Create: %v", err)
+	}
+}
+
+// lastQuery returns the query string of the last request the fake received for path
+func lastQuery(t *testing.T, srv *versafleettest.Server, path string) url.Values {
+	t.Helper()
+	reqs := srv.Requests()
+	for i := len(reqs) - 1; i >= 0; i-- {
+		if reqs[i].URL.Path == path {
+			return reqs[i].URL.Query()
+		}
+	}
+	t.Fatalf("no request for %s", path)
+	return nil
+}
+
+func intPtr(i int) *int { return &i }
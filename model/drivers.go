@@ -46,3 +46,59 @@ type LastSeen struct {
 	Longitude float64   `json:"longitude"`
 	Time      time.Time `json:"time"`
 }
+
+// DriverParams is used for creating drivers
+type DriverParams struct {
+	Name              string        `json:"name"`
+	ContactNumber     string        `json:"contact_number,omitempty"`
+	Username          string        `json:"username,omitempty"`
+	Password          string        `json:"password,omitempty"`
+	License           string        `json:"license,omitempty"`
+	Nric              string        `json:"nric,omitempty"`
+	Dob               string        `json:"dob,omitempty"` // "YYYY-MM-DD"
+	IsVersadriveUser  *bool         `json:"is_versadrive_user,omitempty"`
+	IsAttendant       bool          `json:"is_attendant,omitempty"`
+	SkillList         []string      `json:"skill_list,omitempty"`
+	DefaultVehicleID  *int          `json:"default_vehicle_id,omitempty"`
+	AttendantID       *int          `json:"attendant_id,omitempty"`
+	AddressAttributes *Address      `json:"address_attributes,omitempty"`
+	CustomFields      []CustomField `json:"custom_fields_attributes,omitempty"`
+}
+
+// DriverUpdateParams is used for updating drivers. Only non-nil fields are sent,
+// so point SkillList at an empty slice to clear a driver's skills.
+type DriverUpdateParams struct {
+	Name              *string       `json:"name,omitempty"`
+	ContactNumber     *string       `json:"contact_number,omitempty"`
+	Username          *string       `json:"username,omitempty"`
+	Password          *string       `json:"password,omitempty"`
+	License           *string       `json:"license,omitempty"`
+	Nric              *string       `json:"nric,omitempty"`
+	Dob               *string       `json:"dob,omitempty"`
+	IsVersadriveUser  *bool         `json:"is_versadrive_user,omitempty"`
+	IsAttendant       *bool         `json:"is_attendant,omitempty"`
+	SkillList         *[]string     `json:"skill_list,omitempty"`
+	DefaultVehicleID  *int          `json:"default_vehicle_id,omitempty"`
+	AttendantID       *int          `json:"attendant_id,omitempty"`
+	AddressAttributes *Address      `json:"address_attributes,omitempty"`
+	CustomFields      []CustomField `json:"custom_fields_attributes,omitempty"`
+}
+
+// DriverRequest wraps driver params for create calls
+type DriverRequest struct {
+	Driver *DriverParams `json:"driver"`
+}
+
+// DriverUpdateRequest wraps driver params for update calls
+type DriverUpdateRequest struct {
+	Driver *DriverUpdateParams `json:"driver"`
+}
+
+// DriverListOptions handles filtering for driver list requests
+type DriverListOptions struct {
+	CommonListOptions
+}
+
+type DriverResponse struct {
+	Driver Person `json:"driver"`
+}
@@ -5,7 +5,7 @@ type Job struct {
 	GUID     string   `json:"guid"`
 	JobType  string   `json:"job_type"`
 	Remarks  string   `json:"remarks"`
-	State    string   `json:"state"`
+	State    JobState `json:"state"`
 	Archived bool     `json:"archived"`
 	Customer Customer `json:"customer"`
 	BaseTask BaseTask `json:"base_task"`
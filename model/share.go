@@ -17,15 +17,15 @@ type Address struct {
 }
 
 type BaseTask struct {
-	ID          int      `json:"id"`
-	GUID        string   `json:"guid"`
-	TimeFrom    string   `json:"time_from"`
-	TimeTo      string   `json:"time_to"`
-	TimeType    string   `json:"time_type"`
-	State       string   `json:"state"`
-	Role        string   `json:"role"`
-	ServiceTime int      `json:"service_time"`
-	Address     *Address `json:"address,omitempty"`
+	ID          int       `json:"id"`
+	GUID        string    `json:"guid"`
+	TimeFrom    string    `json:"time_from"`
+	TimeTo      string    `json:"time_to"`
+	TimeType    string    `json:"time_type"`
+	State       TaskState `json:"state"`
+	Role        string    `json:"role"`
+	ServiceTime int       `json:"service_time"`
+	Address     *Address  `json:"address,omitempty"`
 }
 
 type Tag struct {
@@ -0,0 +1,138 @@
+package model
+
+import "fmt"
+
+// TaskState is the lifecycle state of a task (and of a job's base task).
+// States the SDK doesn't know about are kept as-is when decoding; use IsKnown to check.
+type TaskState string
+
+const (
+	TaskStateUnassigned TaskState = "unassigned"
+	TaskStateAssigned   TaskState = "assigned"
+	TaskStateAccepted   TaskState = "accepted"
+	TaskStateDeclined   TaskState = "declined"
+	TaskStateStarted    TaskState = "started"
+	TaskStateArrived    TaskState = "arrived"
+	TaskStateSuccessful TaskState = "successful"
+	TaskStateFailed     TaskState = "failed"
+	TaskStateCancelled  TaskState = "cancelled"
+)
+
+// JobState is the lifecycle state of a job.
+// States the SDK doesn't know about are kept as-is when decoding; use IsKnown to check.
+type JobState string
+
+const (
+	JobStateUnassigned         JobState = "unassigned"
+	JobStateAssigned           JobState = "assigned"
+	JobStateInProgress         JobState = "in_progress"
+	JobStatePartiallyCompleted JobState = "partially_completed"
+	JobStateCompleted          JobState = "completed"
+	JobStateFailed             JobState = "failed"
+	JobStateCancelled          JobState = "cancelled"
+)
+
+// taskTransitions lists the states each task state can move to
+var taskTransitions = map[TaskState][]TaskState{
+	TaskStateUnassigned: {TaskStateAssigned, TaskStateCancelled},
+	TaskStateAssigned:   {TaskStateAssigned, TaskStateUnassigned, TaskStateAccepted, TaskStateDeclined, TaskStateStarted, TaskStateCancelled},
+	TaskStateAccepted:   {TaskStateAssigned, TaskStateUnassigned, TaskStateStarted, TaskStateCancelled},
+	TaskStateDeclined:   {TaskStateAssigned, TaskStateUnassigned, TaskStateCancelled},
+	TaskStateStarted:    {TaskStateArrived, TaskStateSuccessful, TaskStateFailed, TaskStateCancelled},
+	TaskStateArrived:    {TaskStateSuccessful, TaskStateFailed, TaskStateCancelled},
+	TaskStateSuccessful: {},
+	TaskStateFailed:     {TaskStateUnassigned, TaskStateAssigned, TaskStateCancelled}, // Re-attempt
+	TaskStateCancelled:  {},
+}
+
+// jobTransitions lists the states each job state can move to
+var jobTransitions = map[JobState][]JobState{
+	JobStateUnassigned:         {JobStateAssigned, JobStateCancelled},
+	JobStateAssigned:           {JobStateAssigned, JobStateUnassigned, JobStateInProgress, JobStateCancelled},
+	JobStateInProgress:         {JobStatePartiallyCompleted, JobStateCompleted, JobStateFailed, JobStateCancelled},
+	JobStatePartiallyCompleted: {JobStateCompleted, JobStateFailed, JobStateCancelled},
+	JobStateCompleted:          {},
+	JobStateFailed:             {JobStateUnassigned, JobStateAssigned, JobStateCancelled},
+	JobStateCancelled:          {},
+}
+
+// TransitionError is returned when a state change is known to be impossible
+type TransitionError struct {
+	Kind string // "task" or "job"
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("versafleet-sdk: invalid %s state transition %s -> %s", e.Kind, e.From, e.To)
+}
+
+// IsKnown reports whether the state is one of the TaskState constants
+func (s TaskState) IsKnown() bool {
+	_, ok := taskTransitions[s]
+	return ok
+}
+
+// IsFinal reports whether no further transitions are possible from this state
+func (s TaskState) IsFinal() bool {
+	next, ok := taskTransitions[s]
+	return ok && len(next) == 0
+}
+
+// CanTransitionTo reports whether a task may move from s to next.
+// Transitions involving an unknown state are allowed, since only the API can judge them.
+func (s TaskState) CanTransitionTo(next TaskState) bool {
+	allowed, ok := taskTransitions[s]
+	if !ok || !next.IsKnown() {
+		return true
+	}
+	for _, a := range allowed {
+		if a == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTaskTransition returns a *TransitionError if a task cannot move from one state to another
+func ValidateTaskTransition(from, to TaskState) error {
+	if from.CanTransitionTo(to) {
+		return nil
+	}
+	return &TransitionError{Kind: "task", From: string(from), To: string(to)}
+}
+
+// IsKnown reports whether the state is one of the JobState constants
+func (s JobState) IsKnown() bool {
+	_, ok := jobTransitions[s]
+	return ok
+}
+
+// IsFinal reports whether no further transitions are possible from this state
+func (s JobState) IsFinal() bool {
+	next, ok := jobTransitions[s]
+	return ok && len(next) == 0
+}
+
+// CanTransitionTo reports whether a job may move from s to next.
+// Transitions involving an unknown state are allowed, since only the API can judge them.
+func (s JobState) CanTransitionTo(next JobState) bool {
+	allowed, ok := jobTransitions[s]
+	if !ok || !next.IsKnown() {
+		return true
+	}
+	for _, a := range allowed {
+		if a == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateJobTransition returns a *TransitionError if a job cannot move from one state to another
+func ValidateJobTransition(from, to JobState) error {
+	if from.CanTransitionTo(to) {
+		return nil
+	}
+	return &TransitionError{Kind: "job", From: string(from), To: string(to)}
+}
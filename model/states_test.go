@@ -0,0 +1,134 @@
+package model_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Willias7788/go-versafleet-sdk/model"
+)
+
+var taskStates = []model.TaskState{
+	model.TaskStateUnassigned, model.TaskStateAssigned, model.TaskStateAccepted, model.TaskStateDeclined,
+	model.TaskStateStarted, model.TaskStateArrived, model.TaskStateSuccessful, model.TaskStateFailed,
+	model.TaskStateCancelled,
+}
+
+var jobStates = []model.JobState{
+	model.JobStateUnassigned, model.JobStateAssigned, model.JobStateInProgress, model.JobStatePartiallyCompleted,
+	model.JobStateCompleted, model.JobStateFailed, model.JobStateCancelled,
+}
+
+// The lifecycle as the API documents it. Every pair of known states not listed here must be rejected.
+var wantTaskTransitions = map[model.TaskState][]model.TaskState{
+	"unassigned": {"assigned", "cancelled"},
+	"assigned":   {"assigned", "unassigned", "accepted", "declined", "started", "cancelled"},
+	"accepted":   {"assigned", "unassigned", "started", "cancelled"},
+	"declined":   {"assigned", "unassigned", "cancelled"},
+	"started":    {"arrived", "successful", "failed", "cancelled"},
+	"arrived":    {"successful", "failed", "cancelled"},
+	"failed":     {"unassigned", "assigned", "cancelled"},
+}
+
+var wantJobTransitions = map[model.JobState][]model.JobState{
+	"unassigned":          {"assigned", "cancelled"},
+	"assigned":            {"assigned", "unassigned", "in_progress", "cancelled"},
+	"in_progress":         {"partially_completed", "completed", "failed", "cancelled"},
+	"partially_completed": {"completed", "failed", "cancelled"},
+	"failed":              {"unassigned", "assigned", "cancelled"},
+}
+
+func contains[S comparable](list []S, s S) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateTaskTransition(t *testing.T) {
+	for _, from := range taskStates {
+		for _, to := range taskStates {
+			want := contains(wantTaskTransitions[from], to)
+			err := model.ValidateTaskTransition(from, to)
+			if want && err != nil {
+				t.Errorf("%s -> %s rejected: %v", from, to, err)
+			}
+			if !want {
+				var te *model.TransitionError
+				if !errors.As(err, &te) {
+					t.Errorf("%s -> %s allowed, want a *TransitionError", from, to)
+				} else if te.Kind != "task" || te.From != string(from) || te.To != string(to) {
+					t.Errorf("%s -> %s error = %+v", from, to, te)
+				}
+			}
+		}
+	}
+}
+
+func TestValidateJobTransition(t *testing.T) {
+	for _, from := range jobStates {
+		for _, to := range jobStates {
+			want := contains(wantJobTransitions[from], to)
+			err := model.ValidateJobTransition(from, to)
+			if want && err != nil {
+				t.Errorf("%s -> %s rejected: %v", from, to, err)
+			}
+			if !want {
+				var te *model.TransitionError
+				if !errors.As(err, &te) {
+					t.Errorf("%s -> %s allowed, want a *TransitionError", from, to)
+				} else if te.Kind != "job" || te.From != string(from) || te.To != string(to) {
+					t.Errorf("%s -> %s error = %+v", from, to, te)
+				}
+			}
+		}
+	}
+}
+
+func TestUnknownStatesPassThrough(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+	}{
+		{"unknown from", "on_hold", "assigned"},
+		{"unknown to", "successful", "on_hold"},
+		{"both unknown", "on_hold", "paused"},
+		{"empty", "", "assigned"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := model.ValidateTaskTransition(model.TaskState(tt.from), model.TaskState(tt.to)); err != nil {
+				t.Errorf("task: %v", err)
+			}
+			if err := model.ValidateJobTransition(model.JobState(tt.from), model.JobState(tt.to)); err != nil {
+				t.Errorf("job: %v", err)
+			}
+		})
+	}
+}
+
+func TestStateKnownAndFinal(t *testing.T) {
+	for _, s := range taskStates {
+		if !s.IsKnown() {
+			t.Errorf("task state %s not known", s)
+		}
+		if want := s == model.TaskStateSuccessful || s == model.TaskStateCancelled; s.IsFinal() != want {
+			t.Errorf("task state %s IsFinal = %v, want %v", s, s.IsFinal(), want)
+		}
+	}
+	for _, s := range jobStates {
+		if !s.IsKnown() {
+			t.Errorf("job state %s not known", s)
+		}
+		if want := s == model.JobStateCompleted || s == model.JobStateCancelled; s.IsFinal() != want {
+			t.Errorf("job state %s IsFinal = %v, want %v", s, s.IsFinal(), want)
+		}
+	}
+	if model.TaskState("on_hold").IsKnown() || model.TaskState("on_hold").IsFinal() {
+		t.Error("unknown task state reported as known or final")
+	}
+	if model.JobState("on_hold").IsKnown() || model.JobState("on_hold").IsFinal() {
+		t.Error("unknown job state reported as known or final")
+	}
+}
@@ -27,7 +27,7 @@ type Task struct {
 	Invoiced                 bool                 `json:"invoiced"`
 	RecipientName            *string              `json:"recipient_name"`
 	ActualCOD                *float64             `json:"actual_cod"`
-	State                    string               `json:"state"`
+	State                    TaskState            `json:"state"`
 	Role                     string               `json:"role"`
 	Archived                 bool                 `json:"archived"`
 	StateUpdatedAt           string               `json:"state_updated_at"`
@@ -123,3 +123,51 @@ type TaskParams struct {
 	VehiclePartSkillList []string            `json:"vehicle_part_skill_list,omitempty"`
 	DriverSkillList      []string            `json:"driver_skill_list,omitempty"`
 }
+
+// TaskActionRequest wraps the params of a task lifecycle action
+type TaskActionRequest struct {
+	Task TaskActionParams `json:"task,omitempty"`
+}
+
+// TaskActionParams is implemented by the params of the task lifecycle actions that take any
+type TaskActionParams interface {
+	// TaskAction is the action the params belong to, e.g. "assign"
+	TaskAction() string
+}
+
+func (*TaskAssignParams) TaskAction() string     { return "assign" }
+func (*TaskCompleteParams) TaskAction() string   { return "complete" }
+func (*TaskFailParams) TaskAction() string       { return "fail" }
+func (*TaskRescheduleParams) TaskAction() string { return "reschedule" }
+
+// TaskAssignParams assigns a task. Nil IDs are left unchanged.
+type TaskAssignParams struct {
+	DriverID      *int `json:"driver_id,omitempty"`
+	VehicleID     *int `json:"vehicle_id,omitempty"`
+	VehiclePartID *int `json:"vehicle_part_id,omitempty"`
+	AttendantID   *int `json:"attendant_id,omitempty"`
+}
+
+// TaskCompleteParams marks a task as successful
+type TaskCompleteParams struct {
+	RecipientName string   `json:"recipient_name,omitempty"`
+	Notes         string   `json:"notes,omitempty"`
+	ActualCOD     *float64 `json:"actual_cod,omitempty"`
+	ActualTime    *string  `json:"actual_time,omitempty"` // Defaults to now on the server
+}
+
+// TaskFailParams marks a task as failed
+type TaskFailParams struct {
+	Reason     string  `json:"reason"`
+	Notes      string  `json:"notes,omitempty"`
+	ActualTime *string `json:"actual_time,omitempty"` // Defaults to now on the server
+}
+
+// TaskRescheduleParams moves a task to a new time window.
+// Either TimeFrom/TimeTo with a TimeType, or a TimeWindowID, should be set.
+type TaskRescheduleParams struct {
+	TimeFrom     *string  `json:"time_from,omitempty"`
+	TimeTo       *string  `json:"time_to,omitempty"`
+	TimeType     TimeType `json:"time_type,omitempty"`
+	TimeWindowID *int     `json:"time_window_id,omitempty"`
+}
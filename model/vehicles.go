@@ -19,3 +19,55 @@ type Vehicle struct {
 	CustomFieldAttributes *CustomField  `json:"custom_fields_attributes,omitempty"` // for creation & update
 	SkillList             []string      `json:"skill_list,omitempty"`               // for creation & update
 }
+
+// VehicleCreateParams is used for creating vehicles
+type VehicleCreateParams struct {
+	PlateNumber      string        `json:"plate_number"`
+	Status           string        `json:"status,omitempty"`
+	CargoLoad        float64       `json:"cargo_load,omitempty"`
+	Model            string        `json:"model,omitempty"`
+	Category         string        `json:"category,omitempty"`
+	OwnershipDate    string        `json:"ownership_date,omitempty"`
+	RegistrationDate string        `json:"registration_date,omitempty"`
+	InsuranceExpiry  string        `json:"insurance_expiry,omitempty"`
+	TaxExpiry        string        `json:"tax_expiry,omitempty"`
+	SkillList        []string      `json:"skill_list,omitempty"`
+	CustomFields     []CustomField `json:"custom_fields_attributes,omitempty"`
+}
+
+// VehicleUpdateParams is used for updating vehicles. Only non-nil fields are sent,
+// so point SkillList at an empty slice to clear a vehicle's skills.
+type VehicleUpdateParams struct {
+	PlateNumber      *string       `json:"plate_number,omitempty"`
+	Status           *string       `json:"status,omitempty"`
+	CargoLoad        *float64      `json:"cargo_load,omitempty"`
+	Model            *string       `json:"model,omitempty"`
+	Category         *string       `json:"category,omitempty"`
+	OwnershipDate    *string       `json:"ownership_date,omitempty"`
+	RegistrationDate *string       `json:"registration_date,omitempty"`
+	InsuranceExpiry  *string       `json:"insurance_expiry,omitempty"`
+	TaxExpiry        *string       `json:"tax_expiry,omitempty"`
+	SkillList        *[]string     `json:"skill_list,omitempty"`
+	CustomFields     []CustomField `json:"custom_fields_attributes,omitempty"`
+}
+
+// VehicleRequest wraps vehicle params for create calls
+type VehicleRequest struct {
+	Vehicle *VehicleCreateParams `json:"vehicle"`
+}
+
+// VehicleUpdateRequest wraps vehicle params for update calls
+type VehicleUpdateRequest struct {
+	Vehicle *VehicleUpdateParams `json:"vehicle"`
+}
+
+type VehicleResponse struct {
+	Vehicle Vehicle `json:"vehicle"`
+}
+
+// VehicleListOptions handles filtering for vehicle list requests
+type VehicleListOptions struct {
+	CommonListOptions
+	Category *string `url:"category,omitempty" json:"category,omitempty"`
+	Status   *string `url:"status,omitempty" json:"status,omitempty"`
+}
@@ -0,0 +1,262 @@
+package rate
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Observer is implemented by limiters that adjust to the server's responses.
+// The client calls Observe after every response, including retries.
+type Observer interface {
+	Observe(header http.Header, statusCode int)
+}
+
+// Budget is a snapshot of an Adaptive limiter
+type Budget struct {
+	RPS          float64   // Rate currently allowed
+	MaxRPS       float64   // Rate allowed once fully ramped up
+	Burst        int       // Bucket size
+	Available    float64   // Tokens in the bucket: requests that can start without waiting, once any backoff is over
+	BackoffUntil time.Time // No requests start before this; zero if not backing off
+
+	// Last values sent by the server; Remaining is -1 and Reset is zero if it didn't send them
+	Remaining int
+	Reset     time.Time
+}
+
+// Wait returns how long a request would wait for the budget right now
+func (b Budget) Wait() time.Duration {
+	var wait time.Duration
+	if d := time.Until(b.BackoffUntil); d > 0 {
+		wait = d
+	}
+	if b.Available < 1 && b.RPS > 0 {
+		wait += time.Duration((1 - b.Available) / b.RPS * float64(time.Second))
+	}
+	return wait
+}
+
+// Adaptive is a limiter that follows the server's rate-limit headers. It starts at the
+// configured rate, adopts the limit the server advertises, stops every caller until the
+// window resets when the server reports no requests remaining, and on a 429 backs off for
+// Retry-After (or an increasing delay) at a reduced rate, then ramps back up over RampUp.
+type Adaptive struct {
+	Window     time.Duration // Window of the Limit header. Defaults to a minute, as VersaFleet's limit is per minute.
+	RampUp     time.Duration // Time to climb back to the full rate after a 429. Defaults to a minute.
+	MaxBackoff time.Duration // Longest backoff on a 429 without Retry-After. Defaults to a minute.
+
+	mu           sync.Mutex
+	limiter      *rate.Limiter
+	maxRPS       float64
+	burst        int
+	backoffUntil time.Time
+	strikes      int       // Consecutive 429s
+	reducedRPS   float64   // Rate right after the last 429
+	rampFrom     time.Time // When the ramp up started; zero when at full rate
+	remaining    int
+	reset        time.Time
+}
+
+// NewAdaptive creates an adaptive limiter starting at params. A Burst below 1 is raised
+// to 1, as an empty bucket would never let a request through.
+func NewAdaptive(params Params) *Adaptive {
+	burst := params.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	return &Adaptive{
+		limiter:   rate.NewLimiter(rate.Limit(params.RPS), burst),
+		maxRPS:    params.RPS,
+		burst:     burst,
+		remaining: -1,
+	}
+}
+
+// DefaultAdaptive creates an adaptive limiter starting at VersaFleet's 100 req/min, burst 10
+func DefaultAdaptive() *Adaptive {
+	return NewAdaptive(Params{RPS: 100.0 / 60.0, Burst: 10})
+}
+
+// Wait blocks until a backoff in progress is over and the bucket has a token
+func (a *Adaptive) Wait(ctx context.Context) error {
+	for {
+		a.mu.Lock()
+		now := time.Now()
+		a.refresh(now)
+		wait := a.backoffUntil.Sub(now)
+		limiter := a.limiter
+		a.mu.Unlock()
+
+		if wait <= 0 {
+			return limiter.Wait(ctx)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+			// Another 429 may have extended the backoff meanwhile
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Observe adjusts the limiter to a response
+func (a *Adaptive) Observe(header http.Header, statusCode int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+
+	if limit, ok := headerInt(header, "X-RateLimit-Limit", "RateLimit-Limit"); ok && limit > 0 {
+		a.maxRPS = float64(limit) / a.window().Seconds()
+	}
+	if remaining, ok := headerInt(header, "X-RateLimit-Remaining", "RateLimit-Remaining"); ok {
+		a.remaining = remaining
+	}
+	if reset, ok := headerReset(header, now); ok {
+		a.reset = reset
+	}
+
+	if statusCode == http.StatusTooManyRequests {
+		a.strikes++
+		wait, ok := ParseRetryAfter(header, now)
+		if !ok {
+			// 1s, 2s, 4s, ... without Retry-After
+			wait = time.Duration(math.Min(
+				float64(time.Second)*math.Pow(2, float64(a.strikes-1)),
+				float64(a.maxBackoff()),
+			))
+		}
+		a.extendBackoff(now.Add(wait))
+		// Halve the rate on every 429, down to one request per window
+		from := a.currentRPS(now)
+		a.reducedRPS = math.Max(from/2, 1/a.window().Seconds())
+		a.rampFrom = a.backoffUntil
+	} else if statusCode < 500 {
+		a.strikes = 0
+	}
+
+	if a.remaining == 0 && a.reset.After(now) {
+		a.extendBackoff(a.reset)
+	}
+	a.refresh(now)
+}
+
+// Budget returns the limiter's current state
+func (a *Adaptive) Budget() Budget {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	a.refresh(now)
+
+	b := Budget{
+		RPS:       float64(a.limiter.Limit()),
+		MaxRPS:    a.maxRPS,
+		Burst:     a.burst,
+		Available: math.Max(0, a.limiter.TokensAt(now)),
+		Remaining: a.remaining,
+		Reset:     a.reset,
+	}
+	if a.backoffUntil.After(now) {
+		b.BackoffUntil = a.backoffUntil
+	}
+	return b
+}
+
+func (a *Adaptive) extendBackoff(until time.Time) {
+	if until.After(a.backoffUntil) {
+		a.backoffUntil = until
+	}
+}
+
+// currentRPS is the rate allowed at now: reducedRPS right after a backoff, climbing linearly to maxRPS over RampUp
+func (a *Adaptive) currentRPS(now time.Time) float64 {
+	if a.rampFrom.IsZero() {
+		return a.maxRPS
+	}
+	progress := math.Max(0, float64(now.Sub(a.rampFrom))/float64(a.rampUp()))
+	if progress >= 1 {
+		a.rampFrom = time.Time{}
+		return a.maxRPS
+	}
+	return math.Min(a.maxRPS, a.reducedRPS+(a.maxRPS-a.reducedRPS)*progress)
+}
+
+// refresh applies the current rate to the bucket. Callers hold a.mu.
+func (a *Adaptive) refresh(now time.Time) {
+	rps := a.currentRPS(now)
+	if rate.Limit(rps) != a.limiter.Limit() {
+		a.limiter.SetLimitAt(now, rate.Limit(rps))
+	}
+}
+
+func (a *Adaptive) window() time.Duration {
+	if a.Window > 0 {
+		return a.Window
+	}
+	return time.Minute
+}
+
+func (a *Adaptive) rampUp() time.Duration {
+	if a.RampUp > 0 {
+		return a.RampUp
+	}
+	return time.Minute
+}
+
+func (a *Adaptive) maxBackoff() time.Duration {
+	if a.MaxBackoff > 0 {
+		return a.MaxBackoff
+	}
+	return time.Minute
+}
+
+// ParseRetryAfter reads a Retry-After header given either as seconds or as an HTTP date
+func ParseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func headerInt(header http.Header, names ...string) (int, bool) {
+	for _, name := range names {
+		if v := header.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
+
+// headerReset reads the window reset as either seconds from now or a Unix timestamp
+func headerReset(header http.Header, now time.Time) (time.Time, bool) {
+	n, ok := headerInt(header, "X-RateLimit-Reset", "RateLimit-Reset")
+	if !ok || n < 0 {
+		return time.Time{}, false
+	}
+	// Anything below a billion seconds (~31 years) is a delta rather than a timestamp
+	if n < 1e9 {
+		return now.Add(time.Duration(n) * time.Second), true
+	}
+	return time.Unix(int64(n), 0), true
+}
@@ -0,0 +1,118 @@
+package rate_test
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+)
+
+func TestAdaptiveZeroBurst(t *testing.T) {
+	a := rate.NewAdaptive(rate.Params{RPS: 100})
+	if b := a.Budget().Burst; b != 1 {
+		t.Errorf("Burst = %d, want it raised to 1", b)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := a.Wait(ctx); err != nil {
+		t.Fatalf("Wait with Burst 0 = %v", err)
+	}
+}
+
+func TestAdaptive429RetryAfter(t *testing.T) {
+	a := rate.NewAdaptive(rate.Params{RPS: 10, Burst: 5})
+	a.Observe(http.Header{"Retry-After": {"2"}}, http.StatusTooManyRequests)
+
+	b := a.Budget()
+	if d := time.Until(b.BackoffUntil); d < 1500*time.Millisecond || d > 2*time.Second {
+		t.Errorf("backing off for %v, want about 2s", d)
+	}
+	if b.RPS != 5 {
+		t.Errorf("RPS after a 429 = %v, want it halved to 5", b.RPS)
+	}
+
+	// Waiting respects the backoff
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := a.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait during the backoff = %v, want deadline exceeded", err)
+	}
+
+	// Another 429 halves the rate again
+	a.Observe(http.Header{"Retry-After": {"2"}}, http.StatusTooManyRequests)
+	if rps := a.Budget().RPS; rps != 2.5 {
+		t.Errorf("RPS after a second 429 = %v, want 2.5", rps)
+	}
+}
+
+func TestAdaptive429EscalatingBackoff(t *testing.T) {
+	a := rate.NewAdaptive(rate.Params{RPS: 10, Burst: 5})
+	a.MaxBackoff = 3 * time.Second
+
+	// Without Retry-After the backoff doubles from 1s, up to MaxBackoff
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		start := time.Now()
+		a.Observe(http.Header{}, http.StatusTooManyRequests)
+		if got := a.Budget().BackoffUntil.Sub(start); got < want-100*time.Millisecond || got > want+100*time.Millisecond {
+			t.Errorf("429 #%d: backing off for %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestAdaptiveRecovery(t *testing.T) {
+	a := rate.NewAdaptive(rate.Params{RPS: 40, Burst: 1})
+	a.MaxBackoff = 50 * time.Millisecond
+	a.RampUp = 200 * time.Millisecond
+
+	a.Observe(http.Header{}, http.StatusTooManyRequests)
+	start := time.Now()
+	if err := a.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(start); waited < 40*time.Millisecond {
+		t.Errorf("first Wait after the 429 took %v, want the 50ms backoff", waited)
+	}
+
+	// The rate climbs from half back to the full rate over RampUp
+	mid := a.Budget().RPS
+	if mid < 20 || mid >= 40 {
+		t.Errorf("RPS right after the backoff = %v, want between 20 and 40", mid)
+	}
+	time.Sleep(250 * time.Millisecond)
+	if rps := a.Budget().RPS; rps != 40 {
+		t.Errorf("RPS after RampUp = %v, want 40", rps)
+	}
+
+	// A success resets the escalation, so the next 429 backs off for the base delay again
+	a.Observe(http.Header{}, http.StatusTooManyRequests)
+	a.Observe(http.Header{}, http.StatusOK)
+	start = time.Now()
+	a.Observe(http.Header{}, http.StatusTooManyRequests)
+	if got := a.Budget().BackoffUntil.Sub(start); math.Abs(float64(got-50*time.Millisecond)) > float64(20*time.Millisecond) {
+		t.Errorf("backoff after a success = %v, want 50ms", got)
+	}
+}
+
+func TestAdaptiveServerHeaders(t *testing.T) {
+	a := rate.NewAdaptive(rate.Params{RPS: 10, Burst: 5})
+	a.Observe(http.Header{
+		"X-Ratelimit-Limit":     {"120"},
+		"X-Ratelimit-Remaining": {"0"},
+		"X-Ratelimit-Reset":     {"3"},
+	}, http.StatusOK)
+
+	b := a.Budget()
+	if b.MaxRPS != 2 || b.RPS != 2 {
+		t.Errorf("RPS %v, MaxRPS %v; want the advertised 120/min = 2", b.RPS, b.MaxRPS)
+	}
+	if b.Remaining != 0 {
+		t.Errorf("Remaining = %d, want 0", b.Remaining)
+	}
+	if d := time.Until(b.BackoffUntil); d < 2500*time.Millisecond || d > 3*time.Second {
+		t.Errorf("backing off for %v, want until the reset in 3s", d)
+	}
+}
@@ -0,0 +1,88 @@
+package rate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// State is the shared limiter state for one key
+type State struct {
+	// TAT is the theoretical arrival time of the next request (GCRA).
+	// Requests are allowed while TAT is at most one burst ahead of now.
+	TAT time.Time `json:"tat"`
+}
+
+// Store holds limiter state shared between processes.
+// Update must run fn atomically with respect to every other Update on the same key,
+// across all processes sharing the store, and persist the state it returns.
+type Store interface {
+	Update(ctx context.Context, key string, fn func(State) State) error
+}
+
+// Distributed is a limiter whose budget is shared by every process using the same store and key,
+// e.g. all services sharing one VersaFleet account
+type Distributed struct {
+	store    Store
+	key      string
+	interval time.Duration // Time between requests at the steady rate
+	burst    int
+}
+
+// NewDistributed creates a limiter allowing params.RPS requests per second (with bursts of
+// params.Burst) in total across everyone sharing store and key. params.RPS must be positive.
+func NewDistributed(store Store, key string, params Params) (*Distributed, error) {
+	if !(params.RPS > 0) || math.IsInf(params.RPS, 0) {
+		return nil, fmt.Errorf("versafleet-sdk: distributed limiter RPS must be positive, got %v", params.RPS)
+	}
+	burst := params.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	return &Distributed{
+		store:    store,
+		key:      key,
+		interval: time.Duration(float64(time.Second) / params.RPS),
+		burst:    burst,
+	}, nil
+}
+
+// DefaultDistributed creates a distributed limiter with VersaFleet's 100 req/min, burst 10
+func DefaultDistributed(store Store, key string) *Distributed {
+	d, _ := NewDistributed(store, key, Params{RPS: 100.0 / 60.0, Burst: 10})
+	return d
+}
+
+// Wait reserves the next slot in the shared budget and blocks until it arrives.
+// A reservation isn't given back if ctx is cancelled while waiting.
+func (d *Distributed) Wait(ctx context.Context) error {
+	var wait time.Duration
+	err := d.store.Update(ctx, d.key, func(s State) State {
+		now := time.Now()
+		tat := s.TAT
+		if tat.Before(now) {
+			tat = now
+		}
+		// Up to burst requests may be reserved ahead of now
+		tolerance := d.interval * time.Duration(d.burst-1)
+		wait = tat.Sub(now) - tolerance
+		s.TAT = tat.Add(d.interval)
+		return s
+	})
+	if err != nil {
+		return fmt.Errorf("versafleet-sdk: rate limiter store failed: %w", err)
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
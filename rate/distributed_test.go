@@ -0,0 +1,146 @@
+package rate_test
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+)
+
+// waitN calls Wait n times and returns how long each call blocked
+func waitN(t *testing.T, l rate.Limiter, n int) []time.Duration {
+	t.Helper()
+	waits := make([]time.Duration, n)
+	for i := range waits {
+		start := time.Now()
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait %d: %v", i+1, err)
+		}
+		waits[i] = time.Since(start)
+	}
+	return waits
+}
+
+// slack absorbs scheduling noise in the timing assertions
+const slack = 25 * time.Millisecond
+
+func newDistributed(t *testing.T, store rate.Store, key string, params rate.Params) *rate.Distributed {
+	t.Helper()
+	l, err := rate.NewDistributed(store, key, params)
+	if err != nil {
+		t.Fatalf("NewDistributed: %v", err)
+	}
+	return l
+}
+
+func TestNewDistributedRejectsRPS(t *testing.T) {
+	for _, rps := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		if l, err := rate.NewDistributed(rate.NewMemoryStore(), "account", rate.Params{RPS: rps, Burst: 1}); err == nil {
+			t.Errorf("RPS %v: got limiter %+v, want an error", rps, l)
+		}
+	}
+}
+
+func TestDistributedGCRA(t *testing.T) {
+	store := rate.NewMemoryStore()
+	// 20 RPS is one request every 50ms, with 3 allowed at once
+	l := newDistributed(t, store, "account", rate.Params{RPS: 20, Burst: 3})
+
+	start := time.Now()
+	waits := waitN(t, l, 5)
+	for i, w := range waits[:3] {
+		if w > slack {
+			t.Errorf("request %d within the burst waited %v", i+1, w)
+		}
+	}
+	// Past the burst, requests go out at the steady rate
+	for i, w := range waits[3:] {
+		if w < 50*time.Millisecond-slack || w > 50*time.Millisecond+slack {
+			t.Errorf("request %d waited %v, want about 50ms", i+4, w)
+		}
+	}
+
+	// Every request moves the theoretical arrival time on by one interval
+	var tat time.Time
+	store.Update(context.Background(), "account", func(s rate.State) rate.State {
+		tat = s.TAT
+		return s
+	})
+	if ahead := tat.Sub(start); ahead < 250*time.Millisecond-slack || ahead > 250*time.Millisecond+slack {
+		t.Errorf("TAT is %v after the start, want about 250ms", ahead)
+	}
+}
+
+func TestDistributedBurstRefills(t *testing.T) {
+	l := newDistributed(t, rate.NewMemoryStore(), "account", rate.Params{RPS: 20, Burst: 2})
+	waitN(t, l, 2)
+
+	// After two intervals idle the whole burst is available again
+	time.Sleep(100 * time.Millisecond)
+	for i, w := range waitN(t, l, 2) {
+		if w > slack {
+			t.Errorf("request %d after the refill waited %v", i+1, w)
+		}
+	}
+}
+
+func TestDistributedZeroBurst(t *testing.T) {
+	l := newDistributed(t, rate.NewMemoryStore(), "account", rate.Params{RPS: 20})
+	if w := waitN(t, l, 1)[0]; w > slack {
+		t.Errorf("first request with Burst 0 waited %v", w)
+	}
+}
+
+func TestDistributedSharedBudget(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name   string
+		stores func() (rate.Store, rate.Store)
+	}{
+		{"one memory store", func() (rate.Store, rate.Store) {
+			s := rate.NewMemoryStore()
+			return s, s
+		}},
+		{"two file stores on one file", func() (rate.Store, rate.Store) {
+			path := filepath.Join(dir, "budget.json")
+			return &rate.FileStore{Path: path}, &rate.FileStore{Path: path}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s1, s2 := tt.stores()
+			params := rate.Params{RPS: 20, Burst: 2}
+			a := newDistributed(t, s1, "account", params)
+			b := newDistributed(t, s2, "account", params)
+			other := newDistributed(t, s2, "other-account", params)
+
+			// a uses up the shared burst, so b waits a full interval
+			waitN(t, a, 2)
+			if w := waitN(t, b, 1)[0]; w < 50*time.Millisecond-slack {
+				t.Errorf("second client waited %v, want about 50ms", w)
+			}
+			// Another key has its own budget
+			if w := waitN(t, other, 2); w[0] > slack || w[1] > slack {
+				t.Errorf("client on another key waited %v", w)
+			}
+		})
+	}
+}
+
+func TestDistributedWaitCancelled(t *testing.T) {
+	l := newDistributed(t, rate.NewMemoryStore(), "account", rate.Params{RPS: 1, Burst: 1})
+	waitN(t, l, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Wait took %v to give up", elapsed)
+	}
+}
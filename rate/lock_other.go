@@ -0,0 +1,16 @@
+//go:build !unix && !windows
+
+package rate
+
+import (
+	"errors"
+	"os"
+)
+
+func tryLockFile(*os.File) (bool, error) {
+	return false, errors.New("file locking is not supported on this platform")
+}
+
+func unlockFile(*os.File) error {
+	return nil
+}
@@ -0,0 +1,95 @@
+package rate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/internal/atomicfile"
+)
+
+// MemoryStore keeps limiter state in memory. It shares a budget between clients in one
+// process, and is the reference implementation for other stores.
+type MemoryStore struct {
+	mu     sync.Mutex
+	states map[string]State
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{states: make(map[string]State)}
+}
+
+func (m *MemoryStore) Update(_ context.Context, key string, fn func(State) State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[key] = fn(m.states[key])
+	return nil
+}
+
+// FileStore keeps limiter state in a JSON file guarded by an exclusive lock on Path+".lock",
+// so processes on one host (or on a shared filesystem with working locks) share a budget.
+// The file is replaced with a rename, so a crash never leaves it half written.
+type FileStore struct {
+	Path string
+}
+
+// Update waits for the file lock until ctx is done. A state file that can't be decoded,
+// e.g. one left by an older version, is treated as empty and overwritten.
+func (f *FileStore) Update(ctx context.Context, key string, fn func(State) State) error {
+	// The lock lives in its own file, since the rename replaces the state file
+	lock, err := os.OpenFile(f.Path+".lock", os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	if err := lockFile(ctx, lock); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", f.Path, err)
+	}
+	defer unlockFile(lock)
+
+	data, err := os.ReadFile(f.Path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	states := make(map[string]State)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &states); err != nil {
+			// Starting over costs at most one burst, where failing would stop every process
+			states = make(map[string]State)
+		}
+	}
+
+	states[key] = fn(states[key])
+
+	data, err = json.Marshal(states)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(f.Path, data)
+}
+
+// lockFile takes an exclusive lock on f, polling so that ctx can end the wait.
+// The lock is released if the process dies.
+func lockFile(ctx context.Context, f *os.File) error {
+	wait := time.Millisecond
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil || locked {
+			return err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+		wait = min(wait*2, 50*time.Millisecond)
+	}
+}
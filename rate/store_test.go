@@ -0,0 +1,135 @@
+package rate_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+)
+
+func TestFileStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget.json")
+	tat := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	set := func(s rate.State) rate.State { return rate.State{TAT: tat} }
+	if err := (&rate.FileStore{Path: path}).Update(context.Background(), "account", set); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	var got rate.State
+	read := func(s rate.State) rate.State { got = s; return s }
+	if err := (&rate.FileStore{Path: path}).Update(context.Background(), "account", read); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if !got.TAT.Equal(tat) {
+		t.Errorf("TAT = %v, want %v", got.TAT, tat)
+	}
+}
+
+func TestFileStoreLockRespectsContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget.json")
+	holding, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- (&rate.FileStore{Path: path}).Update(context.Background(), "account", func(s rate.State) rate.State {
+			close(holding)
+			<-release
+			return s
+		})
+	}()
+	<-holding
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	called := false
+	err := (&rate.FileStore{Path: path}).Update(ctx, "account", func(s rate.State) rate.State { called = true; return s })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Update while locked = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Update took %v to give up on the lock", elapsed)
+	}
+	if called {
+		t.Error("Update ran fn without the lock")
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("holder's Update: %v", err)
+	}
+	// The lock is free again
+	if err := (&rate.FileStore{Path: path}).Update(context.Background(), "account", func(s rate.State) rate.State { return s }); err != nil {
+		t.Errorf("Update after release: %v", err)
+	}
+}
+
+func TestFileStoreConcurrentUpdates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "budget.json")
+	step := func(s rate.State) rate.State { s.TAT = s.TAT.Add(time.Second); return s }
+
+	const n = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// A store each, as separate processes would have
+			errs <- (&rate.FileStore{Path: path}).Update(context.Background(), "account", step)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+
+	var got rate.State
+	(&rate.FileStore{Path: path}).Update(context.Background(), "account", func(s rate.State) rate.State { got = s; return s })
+	if want := (time.Time{}).Add(n * time.Second); !got.TAT.Equal(want) {
+		t.Errorf("TAT = %v after %d updates, want %v", got.TAT, n, want)
+	}
+
+	// Only the state file and its lock are left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("dir has %d entries, want budget.json and budget.json.lock", len(entries))
+	}
+}
+
+func TestFileStoreCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget.json")
+	if err := os.WriteFile(path, []byte(`{"account":{"tat":"2024-03-`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tat := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	var before rate.State
+	err := (&rate.FileStore{Path: path}).Update(context.Background(), "account", func(s rate.State) rate.State {
+		before = s
+		return rate.State{TAT: tat}
+	})
+	if err != nil {
+		t.Fatalf("Update on a torn file: %v", err)
+	}
+	if !before.TAT.IsZero() {
+		t.Errorf("torn file gave state %+v, want empty", before)
+	}
+
+	var got rate.State
+	(&rate.FileStore{Path: path}).Update(context.Background(), "account", func(s rate.State) rate.State { got = s; return s })
+	if !got.TAT.Equal(tat) {
+		t.Errorf("TAT = %v after rewriting, want %v", got.TAT, tat)
+	}
+}
@@ -3,6 +3,8 @@ package tasks
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/Willias7788/go-versafleet-sdk/client"
 	"github.com/Willias7788/go-versafleet-sdk/model"
@@ -23,19 +25,26 @@ type TaskListResponse struct {
 
 // List returns an iterator to list all tasks
 func (s *Service) List(ctx context.Context, opts *model.TaskListOptions) *client.Iterator[model.Task, *model.TaskListOptions] {
-	return client.NewIterator(ctx, s.client, "/tasks", opts, func(ctx context.Context, path string, opts *model.TaskListOptions) ([]model.Task, *model.Meta, error) {
-		var resp TaskListResponse
-		path = fmt.Sprintf("%s?page=%d&per_page=%d", path, opts.Page, opts.PerPage)
+	return client.NewIterator(ctx, s.client, "/tasks", opts, s.listPage)
+}
 
-		if err := s.client.Get(ctx, path, &resp); err != nil {
-			return nil, nil, err
-		}
-		return resp.Tasks, resp.Meta, nil
-	})
+// ResumeList continues listing tasks from a checkpoint saved by an earlier iterator
+func (s *Service) ResumeList(ctx context.Context, cp *client.Checkpoint) (*client.Iterator[model.Task, *model.TaskListOptions], error) {
+	return client.ResumeIterator(ctx, s.client, cp, s.listPage)
+}
+
+func (s *Service) listPage(ctx context.Context, path string, opts *model.TaskListOptions) ([]model.Task, *model.Meta, error) {
+	ctx = client.WithOperation(ctx, "tasks.List")
+	var resp TaskListResponse
+	if err := s.client.GetWithQuery(ctx, path, opts, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Tasks, resp.Meta, nil
 }
 
 // Get retrieves a single task by ID
 func (s *Service) Get(ctx context.Context, id string) (*model.Task, error) {
+	ctx = client.WithOperation(ctx, "tasks.Get")
 	var task model.Task
 	path := fmt.Sprintf("/tasks/%s", id)
 	err := s.client.Get(ctx, path, &task)
@@ -45,8 +54,10 @@ func (s *Service) Get(ctx context.Context, id string) (*model.Task, error) {
 	return &task, nil
 }
 
-// Get retrieves a single task by ID
+// Update updates a task's attributes. The request carries the context's idempotency key, or a generated one.
 func (s *Service) Update(ctx context.Context, id string, taskUpdate *model.TaskParams) (*model.Task, error) {
+	ctx = client.WithOperation(ctx, "tasks.Update")
+	ctx, _ = client.EnsureIdempotencyKey(ctx)
 	var task model.Task
 	path := fmt.Sprintf("/tasks/%s", id)
 	taskRequest := model.TaskRequest{
@@ -58,3 +69,79 @@ func (s *Service) Update(ctx context.Context, id string, taskUpdate *model.TaskP
 	}
 	return &task, nil
 }
+
+// Assign assigns a driver, vehicle, vehicle part and/or attendant to a task
+func (s *Service) Assign(ctx context.Context, id string, params *model.TaskAssignParams) (*model.Task, error) {
+	return s.action(ctx, id, "assign", params)
+}
+
+// Unassign removes the task's current assignment
+func (s *Service) Unassign(ctx context.Context, id string) (*model.Task, error) {
+	return s.action(ctx, id, "unassign", nil)
+}
+
+// Start marks an assigned task as started
+func (s *Service) Start(ctx context.Context, id string) (*model.Task, error) {
+	return s.action(ctx, id, "start", nil)
+}
+
+// Complete marks a task as successful
+func (s *Service) Complete(ctx context.Context, id string, params *model.TaskCompleteParams) (*model.Task, error) {
+	return s.action(ctx, id, "complete", params)
+}
+
+// Fail marks a task as failed with a reason
+func (s *Service) Fail(ctx context.Context, id string, params *model.TaskFailParams) (*model.Task, error) {
+	return s.action(ctx, id, "fail", params)
+}
+
+// Cancel cancels a task
+func (s *Service) Cancel(ctx context.Context, id string) (*model.Task, error) {
+	return s.action(ctx, id, "cancel", nil)
+}
+
+// Reschedule moves a task to a new time window
+func (s *Service) Reschedule(ctx context.Context, id string, params *model.TaskRescheduleParams) (*model.Task, error) {
+	return s.action(ctx, id, "reschedule", params)
+}
+
+// transitionActions maps a target state to the lifecycle action that reaches it
+var transitionActions = map[model.TaskState]string{
+	model.TaskStateAssigned:   "assign",
+	model.TaskStateUnassigned: "unassign",
+	model.TaskStateStarted:    "start",
+	model.TaskStateSuccessful: "complete",
+	model.TaskStateFailed:     "fail",
+	model.TaskStateCancelled:  "cancel",
+}
+
+// Transition moves a task to the given state with the matching lifecycle action.
+// The move is checked locally against task.State first, so an impossible transition
+// returns a *model.TransitionError without making an API call.
+// params must be the action's params type (e.g. *model.TaskFailParams), or nil.
+func (s *Service) Transition(ctx context.Context, task *model.Task, to model.TaskState, params model.TaskActionParams) (*model.Task, error) {
+	if err := model.ValidateTaskTransition(task.State, to); err != nil {
+		return nil, err
+	}
+	action, ok := transitionActions[to]
+	if !ok {
+		return nil, fmt.Errorf("versafleet-sdk: no task action leads to state %s", to)
+	}
+	if params != nil && params.TaskAction() != action {
+		return nil, fmt.Errorf("versafleet-sdk: %T are not params for the %s action", params, action)
+	}
+	return s.action(ctx, strconv.Itoa(task.ID), action, params)
+}
+
+// action performs a lifecycle action (PUT /tasks/:id/:action) and returns the updated task
+func (s *Service) action(ctx context.Context, id, action string, params model.TaskActionParams) (*model.Task, error) {
+	// Named after the method, e.g. tasks.Assign
+	ctx = client.WithOperation(ctx, "tasks."+strings.ToUpper(action[:1])+action[1:])
+	var task model.Task
+	path := fmt.Sprintf("/tasks/%s/%s", id, action)
+	err := s.client.Put(ctx, path, model.TaskActionRequest{Task: params}, &task)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
@@ -0,0 +1,105 @@
+package tasks_test
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+	"github.com/Willias7788/go-versafleet-sdk/tasks"
+	"github.com/Willias7788/go-versafleet-sdk/versafleettest"
+)
+
+func TestListFiltersReachTheWire(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	svc := tasks.New(client.New(srv.Config(), client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100}))))
+
+	tests := []struct {
+		name string
+		opts *model.TaskListOptions
+		want url.Values
+	}{
+		{"created range", &model.TaskListOptions{FromCreatedAt: strPtr("2024-03-01"), ToCreatedAt: strPtr("2024-03-31")}, url.Values{"from_created_at": {"2024-03-01"}, "to_created_at": {"2024-03-31"}}},
+		{"time type", &model.TaskListOptions{TimeType: strPtr("completed")}, url.Values{"time_type": {"completed"}}},
+		{"tracking id", &model.TaskListOptions{TrackingID: strPtr("TRK-1")}, url.Values{"tracking_id": {"TRK-1"}}},
+		{"ids", &model.TaskListOptions{CustomerID: intPtr(7), JobID: intPtr(8), ID: intPtr(9)}, url.Values{"customer_id": {"7"}, "job_id": {"8"}, "id": {"9"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := svc.List(context.Background(), tt.opts)
+			defer it.Close()
+			it.Next()
+			if err := it.Err(); err != nil {
+				t.Fatalf("List: %v", err)
+			}
+
+			got := lastQuery(t, srv, "/tasks")
+			for key, want := range tt.want {
+				if !reflect.DeepEqual(got[key], want) {
+					t.Errorf("%s = %q, want %q (query %s)", key, got[key], want, got.Encode())
+				}
+			}
+		})
+	}
+}
+
+func TestTransitionTypedParams(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	svc := tasks.New(client.New(srv.Config(), client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100}))))
+	ctx := context.Background()
+
+	driver := srv.AddDriver(model.Person{Name: "Ah Tan"})
+	job := srv.AddJob(model.JobParams{TasksAttributes: []model.TaskParams{{TrackingID: "TRK-1"}}})
+	task, _ := srv.Task(job.Tasks[0].ID)
+
+	// Params for another action are rejected before anything is sent
+	sent := len(srv.Requests())
+	_, err := svc.Transition(ctx, &task, model.TaskStateAssigned, &model.TaskFailParams{Reason: "closed"})
+	if err == nil || !strings.Contains(err.Error(), "assign") {
+		t.Fatalf("Transition with fail params = %v, want an error naming the assign action", err)
+	}
+	if len(srv.Requests()) != sent {
+		t.Error("Transition with mismatched params made a request")
+	}
+
+	got, err := svc.Transition(ctx, &task, model.TaskStateAssigned, &model.TaskAssignParams{DriverID: &driver.ID})
+	if err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+	if got.State != model.TaskStateAssigned || got.TaskAssignment == nil || got.TaskAssignment.Driver.ID != driver.ID {
+		t.Errorf("after assign: state %s, assignment %+v", got.State, got.TaskAssignment)
+	}
+
+	if got, err = svc.Transition(ctx, got, model.TaskStateStarted, nil); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	got, err = svc.Transition(ctx, got, model.TaskStateFailed, &model.TaskFailParams{Reason: "closed"})
+	if err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	if got.State != model.TaskStateFailed || got.LatestFailureReason != "closed" {
+		t.Errorf("after fail: state %s, reason %q", got.State, got.LatestFailureReason)
+	}
+}
+
+// lastQuery returns the query string of the last request the fake received for path
+func lastQuery(t *testing.T, srv *versafleettest.Server, path string) url.Values {
+	t.Helper()
+	reqs := srv.Requests()
+	for i := len(reqs) - 1; i >= 0; i-- {
+		if reqs[i].URL.Path == path {
+			return reqs[i].URL.Query()
+		}
+	}
+	t.Fatalf("no request for %s", path)
+	return nil
+}
+
+func strPtr(s string) *string { return &s }
+func intPtr(i int) *int       { return &i }
@@ -33,6 +33,7 @@ type PresignedURLResponse struct {
 
 // GetPresignedURL helps to get the pre-signed URL for uploading a file
 func (s *Service) GetPresignedURL(ctx context.Context, taskId, filename string) (*PresignedURLResponse, error) {
+	ctx = client.WithOperation(ctx, "upload.GetPresignedURL")
 	var resp PresignedURLResponse
 	// Note: Endpoint path is inferred. Please verify with official documentation.
 	// Common patterns: /attachments/new, /files/storage_request
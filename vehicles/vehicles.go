@@ -0,0 +1,107 @@
+package vehicles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+)
+
+type Service struct {
+	client *client.Client
+}
+
+func New(c *client.Client) *Service {
+	return &Service{client: c}
+}
+
+type VehicleListResponse struct {
+	Vehicles []model.Vehicle `json:"vehicles"`
+	Meta     *model.Meta     `json:"meta"`
+}
+
+// List returns an iterator to list all vehicles
+func (s *Service) List(ctx context.Context, opts *model.VehicleListOptions) *client.Iterator[model.Vehicle, *model.VehicleListOptions] {
+	return client.NewIterator(ctx, s.client, "/vehicles", opts, s.listPage)
+}
+
+// ResumeList continues listing vehicles from a checkpoint saved by an earlier iterator
+func (s *Service) ResumeList(ctx context.Context, cp *client.Checkpoint) (*client.Iterator[model.Vehicle, *model.VehicleListOptions], error) {
+	return client.ResumeIterator(ctx, s.client, cp, s.listPage)
+}
+
+func (s *Service) listPage(ctx context.Context, path string, opts *model.VehicleListOptions) ([]model.Vehicle, *model.Meta, error) {
+	ctx = client.WithOperation(ctx, "vehicles.List")
+	var resp VehicleListResponse
+	if err := s.client.GetWithQuery(ctx, path, opts, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Vehicles, resp.Meta, nil
+}
+
+// Get retrieves a single vehicle by ID
+func (s *Service) Get(ctx context.Context, id string) (*model.Vehicle, error) {
+	ctx = client.WithOperation(ctx, "vehicles.Get")
+	var resp model.VehicleResponse
+	path := fmt.Sprintf("/vehicles/%s", id)
+	err := s.client.Get(ctx, path, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Vehicle, nil
+}
+
+// Create creates a new vehicle
+func (s *Service) Create(ctx context.Context, params *model.VehicleCreateParams) (*model.Vehicle, error) {
+	ctx = client.WithOperation(ctx, "vehicles.Create")
+	var resp model.VehicleResponse
+	err := s.client.Post(ctx, "/vehicles", model.VehicleRequest{Vehicle: params}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Vehicle, nil
+}
+
+// Update updates an existing vehicle, changing only the fields set in params
+func (s *Service) Update(ctx context.Context, id string, params *model.VehicleUpdateParams) (*model.Vehicle, error) {
+	ctx = client.WithOperation(ctx, "vehicles.Update")
+	var resp model.VehicleResponse
+	path := fmt.Sprintf("/vehicles/%s", id)
+	err := s.client.Put(ctx, path, model.VehicleUpdateRequest{Vehicle: params}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Vehicle, nil
+}
+
+// Archive archives a vehicle so it can no longer be assigned to tasks
+func (s *Service) Archive(ctx context.Context, id string) (*model.Vehicle, error) {
+	ctx = client.WithOperation(ctx, "vehicles.Archive")
+	var resp model.VehicleResponse
+	path := fmt.Sprintf("/vehicles/%s/archive", id)
+	err := s.client.Put(ctx, path, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Vehicle, nil
+}
+
+// Unarchive restores an archived vehicle
+func (s *Service) Unarchive(ctx context.Context, id string) (*model.Vehicle, error) {
+	ctx = client.WithOperation(ctx, "vehicles.Unarchive")
+	var resp model.VehicleResponse
+	path := fmt.Sprintf("/vehicles/%s/unarchive", id)
+	err := s.client.Put(ctx, path, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Vehicle, nil
+}
+
+// Delete deletes a vehicle
+func (s *Service) Delete(ctx context.Context, id string) error {
+	ctx = client.WithOperation(ctx, "vehicles.Delete")
+	path := fmt.Sprintf("/vehicles/%s", id)
+	return s.client.Delete(ctx, path)
+}
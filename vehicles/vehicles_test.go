@@ -0,0 +1,169 @@
+package vehicles_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+	"github.com/Willias7788/go-versafleet-sdk/vehicles"
+	"github.com/Willias7788/go-versafleet-sdk/versafleettest"
+)
+
+func newService(srv *versafleettest.Server, opts ...client.Option) *vehicles.Service {
+	opts = append([]client.Option{client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100}))}, opts...)
+	return vehicles.New(client.New(srv.Config(), opts...))
+}
+
+func TestListFiltersReachTheWire(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	svc := newService(srv)
+
+	tests := []struct {
+		name string
+		opts *model.VehicleListOptions
+		want url.Values
+	}{
+		{"category and status", &model.VehicleListOptions{Category: strPtr("truck"), Status: strPtr("active")}, url.Values{"category": {"truck"}, "status": {"active"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := svc.List(context.Background(), tt.opts)
+			defer it.Close()
+			it.Next()
+			if err := it.Err(); err != nil {
+				t.Fatalf("List: %v", err)
+			}
+
+			got := lastQuery(t, srv, "/vehicles")
+			for key, want := range tt.want {
+				if !reflect.DeepEqual(got[key], want) {
+					t.Errorf("%s = %q, want %q (query %s)", key, got[key], want, got.Encode())
+				}
+			}
+		})
+	}
+}
+
+func TestCRUD(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	svc := newService(srv)
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, &model.VehicleCreateParams{PlateNumber: "SGX1234A", Model: "Transit", Category: "van", SkillList: []string{"cold chain"}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ID == 0 || created.PlateNumber != "SGX1234A" || !reflect.DeepEqual(created.Skills, []string{"cold chain"}) {
+		t.Errorf("Create = %+v", created)
+	}
+	id := strconv.Itoa(created.ID)
+
+	got, err := svc.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.PlateNumber != "SGX1234A" || got.Model != "Transit" {
+		t.Errorf("Get = %+v", got)
+	}
+
+	archived, err := svc.Archive(ctx, id)
+	if err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	if archived.Status != "archived" {
+		t.Errorf("status after Archive = %q", archived.Status)
+	}
+	unarchived, err := svc.Unarchive(ctx, id)
+	if err != nil {
+		t.Fatalf("Unarchive: %v", err)
+	}
+	if unarchived.Status != "active" {
+		t.Errorf("status after Unarchive = %q", unarchived.Status)
+	}
+
+	if err := svc.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	var apiErr *client.APIError
+	if _, err := svc.Get(ctx, id); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Get after Delete: err = %v, want a 404", err)
+	}
+
+	// The plate number is required on create
+	if _, err := svc.Create(ctx, &model.VehicleCreateParams{Model: "Transit"}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Create without a plate number: err = %v, want a 422", err)
+	}
+}
+
+func TestPartialUpdate(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	// Keep the body of every update as sent
+	var sent []string
+	record := func(next client.RoundTripper) client.RoundTripper {
+		return client.RoundTripperFunc(func(call *client.Call) (*client.Response, error) {
+			if call.Operation == "vehicles.Update" {
+				body, _ := json.Marshal(call.Body)
+				sent = append(sent, string(body))
+			}
+			return next.RoundTrip(call)
+		})
+	}
+	svc := newService(srv, client.WithMiddleware(record))
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, &model.VehicleCreateParams{PlateNumber: "SGX1234A", Category: "van", CargoLoad: 500, SkillList: []string{"cold chain"}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	id := strconv.Itoa(created.ID)
+
+	// Only the model is sent, so the plate number, category, load and skills are kept
+	updated, err := svc.Update(ctx, id, &model.VehicleUpdateParams{Model: strPtr("Transit")})
+	if err != nil {
+		t.Fatalf("Update model: %v", err)
+	}
+	if updated.PlateNumber != "SGX1234A" || updated.Category != "van" || updated.CargoLoad != 500 ||
+		updated.Model != "Transit" || !reflect.DeepEqual(updated.Skills, []string{"cold chain"}) {
+		t.Errorf("Update model = %+v", updated)
+	}
+
+	// An empty list clears the skills
+	updated, err = svc.Update(ctx, id, &model.VehicleUpdateParams{SkillList: &[]string{}})
+	if err != nil {
+		t.Fatalf("Update skills: %v", err)
+	}
+	if len(updated.Skills) != 0 || updated.Model != "Transit" {
+		t.Errorf("Update skills = %+v", updated)
+	}
+
+	want := []string{`{"vehicle":{"model":"Transit"}}`, `{"vehicle":{"skill_list":[]}}`}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent %q, want %q", sent, want)
+	}
+}
+
+// lastQuery returns the query string of the last request the fake received for path
+func lastQuery(t *testing.T, srv *versafleettest.Server, path string) url.Values {
+	t.Helper()
+	reqs := srv.Requests()
+	for i := len(reqs) - 1; i >= 0; i-- {
+		if reqs[i].URL.Path == path {
+			return reqs[i].URL.Query()
+		}
+	}
+	t.Fatalf("no request for %s", path)
+	return nil
+}
+
+func strPtr(s string) *string { return &s }
@@ -0,0 +1,756 @@
+package versafleettest
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/account"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+)
+
+// Seeding and inspection. These are safe to call while the server is handling requests.
+
+// AddCustomer stores a customer, assigning an ID if it has none, and returns the stored copy
+func (s *Server) AddCustomer(c model.Customer) model.Customer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addCustomer(c).Customer
+}
+
+// AddDriver stores a driver, assigning an ID if it has none
+func (s *Server) AddDriver(d model.Person) model.Person {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d.ID == 0 {
+		d.ID = s.newID()
+	}
+	s.drivers[d.ID] = &d
+	return d
+}
+
+// AddVehicle stores a vehicle, assigning an ID if it has none
+func (s *Server) AddVehicle(v model.Vehicle) model.Vehicle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v.ID == 0 {
+		v.ID = s.newID()
+	}
+	s.vehicles[v.ID] = &v
+	return v
+}
+
+// AddAccount stores an account, assigning an ID if it has none
+func (s *Server) AddAccount(a account.Account) account.Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a.ID == 0 {
+		a.ID = int64(s.newID())
+	}
+	s.accounts[int(a.ID)] = &a
+	return a
+}
+
+// AddJob creates a job (and its tasks) as if it had been POSTed
+func (s *Server) AddJob(params model.JobParams) model.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.createJob(&params)
+}
+
+// Task returns the stored task
+func (s *Server) Task(id int) (model.Task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[id]
+	if !ok {
+		return model.Task{}, false
+	}
+	return *t, true
+}
+
+// Job returns the stored job with its current tasks
+func (s *Server) Job(id int) (model.Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return model.Job{}, false
+	}
+	return s.jobView(j), true
+}
+
+// Jobs
+
+func (s *Server) routeJobs(w http.ResponseWriter, r *http.Request, seg []string, id int) {
+	switch {
+	case len(seg) == 1 && r.Method == http.MethodGet:
+		f := filter{r.URL.Query()}
+		var items []model.Job
+		for _, jid := range sortedIDs(s.jobs) {
+			j := s.jobs[jid]
+			if f.keyword(j.JobType, j.Remarks, j.Customer.Name) && f.str("state", string(j.State)) &&
+				f.archived(j.Archived) && f.int("customer_id", j.Customer.ID) && f.timeRange(j.BaseTask.TimeFrom) {
+				items = append(items, s.jobView(j))
+			}
+		}
+		page, meta := paginate(items, r.URL.Query())
+		writeJSON(w, http.StatusOK, map[string]interface{}{"jobs": page, "meta": meta})
+	case len(seg) == 1 && r.Method == http.MethodPost:
+		var params model.JobParams
+		if !decodeBody(w, r, &params) {
+			return
+		}
+		if errs := validateJob(&params); errs != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed", errs)
+			return
+		}
+		writeJSON(w, http.StatusCreated, model.JobResponse{Job: *s.createJob(&params)})
+	case len(seg) == 2:
+		j, ok := s.jobs[id]
+		if !ok {
+			notFound(w, "Job", id)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, s.jobView(j))
+		case http.MethodPut:
+			var params model.JobUpdateParams
+			if !decodeBody(w, r, &params) {
+				return
+			}
+			if params.JobType != "" {
+				j.JobType = params.JobType
+			}
+			if params.Remarks != "" {
+				j.Remarks = params.Remarks
+			}
+			if params.TagList != nil {
+				j.Tags = tags(params.TagList)
+			}
+			if params.CustomerID != 0 {
+				if c, ok := s.customers[params.CustomerID]; ok {
+					j.Customer = c.Customer
+				}
+			}
+			writeJSON(w, http.StatusOK, model.JobResponse{Job: s.jobView(j)})
+		case http.MethodDelete:
+			for _, t := range j.Tasks {
+				delete(s.tasks, t.ID)
+			}
+			delete(s.jobs, id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w)
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Not Found", nil)
+	}
+}
+
+func validateJob(p *model.JobParams) map[string][]string {
+	errs := map[string][]string{}
+	if p.JobType == "" {
+		errs["job_type"] = append(errs["job_type"], "can't be blank")
+	}
+	if p.CustomerID == 0 {
+		errs["customer_id"] = append(errs["customer_id"], "can't be blank")
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// createJob stores a job and its tasks. Callers hold s.mu.
+func (s *Server) createJob(p *model.JobParams) *model.Job {
+	job := &model.Job{
+		ID:      s.newID(),
+		JobType: p.JobType,
+		Remarks: p.Remarks,
+		State:   model.JobStateUnassigned,
+		Tags:    tags(p.TagList),
+	}
+	job.GUID = fmt.Sprintf("job-%d", job.ID)
+	if c, ok := s.customers[p.CustomerID]; ok {
+		job.Customer = c.Customer
+	} else {
+		job.Customer = model.Customer{ID: p.CustomerID}
+	}
+	job.BaseTask = model.BaseTask{ID: s.newID(), State: model.TaskStateUnassigned, Role: "base"}
+	if bt := p.BaseTaskAttributes; bt != nil {
+		job.BaseTask.TimeFrom = deref(bt.TimeFrom)
+		job.BaseTask.TimeTo = deref(bt.TimeTo)
+		if bt.TimeType != nil {
+			job.BaseTask.TimeType = string(*bt.TimeType)
+		}
+		job.BaseTask.ServiceTime = bt.ServiceTime
+		job.BaseTask.Address = bt.AddressAttributes
+	}
+
+	for _, tp := range p.TasksAttributes {
+		task := &model.Task{ID: s.newID(), JobID: job.ID, State: model.TaskStateUnassigned, Role: "task"}
+		task.GUID = fmt.Sprintf("task-%d", task.ID)
+		applyTaskParams(task, &tp)
+		s.tasks[task.ID] = task
+		job.Tasks = append(job.Tasks, model.Task{ID: task.ID})
+	}
+	s.jobs[job.ID] = job
+	view := s.jobView(job)
+	return &view
+}
+
+// jobView returns a copy of the job with its tasks' current state filled in. Callers hold s.mu.
+func (s *Server) jobView(j *model.Job) model.Job {
+	view := *j
+	view.Tasks = nil
+	for _, ref := range j.Tasks {
+		if t, ok := s.tasks[ref.ID]; ok {
+			view.Tasks = append(view.Tasks, *t)
+		}
+	}
+	return view
+}
+
+func applyTaskParams(t *model.Task, p *model.TaskParams) {
+	if p.Price != 0 {
+		t.Price = p.Price
+	}
+	if p.InvoiceNumber != "" {
+		t.InvoiceNumber = p.InvoiceNumber
+	}
+	if p.TrackingID != "" {
+		t.TrackingID = p.TrackingID
+	}
+	if p.TimeFrom != nil {
+		t.TimeFrom = *p.TimeFrom
+	}
+	if p.TimeTo != nil {
+		t.TimeTo = *p.TimeTo
+	}
+	if p.TimeType != "" {
+		t.TimeType = string(p.TimeType)
+	}
+	if p.TimeWindowID != nil {
+		t.TimeWindowID = p.TimeWindowID
+	}
+	if p.ExpectedCod != 0 {
+		t.ExpectedCOD = p.ExpectedCod
+	}
+	if p.Remarks != "" {
+		t.Remarks = p.Remarks
+	}
+	if p.ServiceTime != 0 {
+		t.ServiceTime = p.ServiceTime
+	}
+	if p.AddressAttributes != nil {
+		t.Address = p.AddressAttributes
+	}
+	if p.TagList != nil {
+		t.Tags = tags(p.TagList)
+	}
+	if p.CustomFields != nil {
+		t.CustomFields = p.CustomFields
+	}
+	for _, m := range p.Measurements {
+		t.Measurements = append(t.Measurements, model.Measurement{
+			Quantity: m.Quantity, QuantityUnit: m.QuantityUnit, Weight: m.Weight,
+			Volume: m.Volume, Description: m.Description, CustomItemID: m.CustomItemID,
+		})
+	}
+}
+
+func tags(names []string) []model.Tag {
+	var out []model.Tag
+	for _, n := range names {
+		out = append(out, model.Tag{Name: n})
+	}
+	return out
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Tasks
+
+func (s *Server) routeTasks(w http.ResponseWriter, r *http.Request, seg []string, id int) {
+	if len(seg) == 1 {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		f := filter{r.URL.Query()}
+		var items []model.Task
+		for _, tid := range sortedIDs(s.tasks) {
+			t := s.tasks[tid]
+			customerID := 0
+			if j, ok := s.jobs[t.JobID]; ok {
+				customerID = j.Customer.ID
+			}
+			if f.keyword(t.TrackingID, t.InvoiceNumber, t.Remarks) && f.str("state", string(t.State)) &&
+				f.archived(t.Archived) && f.int("customer_id", customerID) && f.int("job_id", t.JobID) &&
+				f.int("id", t.ID) && f.str("tracking_id", t.TrackingID) && f.str("time_type", t.TimeType) &&
+				f.timeRange(t.TimeFrom) {
+				items = append(items, *t)
+			}
+		}
+		page, meta := paginate(items, r.URL.Query())
+		writeJSON(w, http.StatusOK, map[string]interface{}{"tasks": page, "meta": meta})
+		return
+	}
+
+	t, ok := s.tasks[id]
+	if !ok {
+		notFound(w, "Task", id)
+		return
+	}
+
+	if len(seg) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, t)
+		case http.MethodPut:
+			var req model.TaskRequest
+			if !decodeBody(w, r, &req) {
+				return
+			}
+			applyTaskParams(t, &req.TaskAttributes)
+			writeJSON(w, http.StatusOK, t)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	if len(seg) != 3 || r.Method != http.MethodPut {
+		writeError(w, http.StatusNotFound, "Not Found", nil)
+		return
+	}
+	s.taskAction(w, r, t, seg[2])
+}
+
+var actionStates = map[string]model.TaskState{
+	"assign":     model.TaskStateAssigned,
+	"unassign":   model.TaskStateUnassigned,
+	"start":      model.TaskStateStarted,
+	"complete":   model.TaskStateSuccessful,
+	"fail":       model.TaskStateFailed,
+	"cancel":     model.TaskStateCancelled,
+	"reschedule": "",
+}
+
+// taskAction applies a lifecycle action, rejecting impossible transitions with 422. Callers hold s.mu.
+func (s *Server) taskAction(w http.ResponseWriter, r *http.Request, t *model.Task, action string) {
+	to, ok := actionStates[action]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found", nil)
+		return
+	}
+	if to != "" {
+		if err := model.ValidateTaskTransition(t.State, to); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error(), map[string][]string{"state": {"invalid transition"}})
+			return
+		}
+	}
+
+	var req struct {
+		Task struct {
+			model.TaskAssignParams
+			model.TaskCompleteParams
+			model.TaskRescheduleParams
+			Reason string `json:"reason"`
+		} `json:"task"`
+	}
+	if r.ContentLength != 0 && !decodeBody(w, r, &req) {
+		return
+	}
+	p := req.Task
+
+	switch action {
+	case "assign":
+		a := t.TaskAssignment
+		if a == nil {
+			a = &model.TaskAssignment{}
+		}
+		if p.DriverID != nil {
+			d, ok := s.drivers[*p.DriverID]
+			if !ok {
+				notFound(w, "Driver", *p.DriverID)
+				return
+			}
+			a.Driver = model.Entity{ID: d.ID, Name: d.Name, ContactNumber: d.ContactNumber}
+		}
+		if p.VehicleID != nil {
+			v, ok := s.vehicles[*p.VehicleID]
+			if !ok {
+				notFound(w, "Vehicle", *p.VehicleID)
+				return
+			}
+			a.Vehicle = *v
+		}
+		if p.VehiclePartID != nil {
+			v, ok := s.vehicles[*p.VehiclePartID]
+			if !ok {
+				notFound(w, "Vehicle", *p.VehiclePartID)
+				return
+			}
+			a.VehiclePart = *v
+		}
+		if p.AttendantID != nil {
+			d, ok := s.drivers[*p.AttendantID]
+			if !ok {
+				notFound(w, "Attendant", *p.AttendantID)
+				return
+			}
+			a.Attendant = model.Entity{ID: d.ID, Name: d.Name, ContactNumber: d.ContactNumber}
+		}
+		t.TaskAssignment = a
+	case "unassign":
+		t.TaskAssignment = nil
+	case "complete":
+		if p.RecipientName != "" {
+			t.RecipientName = &p.RecipientName
+		}
+		t.ActualCOD = p.ActualCOD
+		t.ActualTime = p.TaskCompleteParams.ActualTime
+	case "fail":
+		if p.Reason == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation failed", map[string][]string{"reason": {"can't be blank"}})
+			return
+		}
+		t.LatestFailureReason = p.Reason
+	case "reschedule":
+		if p.TimeFrom != nil {
+			t.TimeFrom = *p.TimeFrom
+		}
+		if p.TimeTo != nil {
+			t.TimeTo = *p.TimeTo
+		}
+		if p.TimeType != "" {
+			t.TimeType = string(p.TimeType)
+		}
+		if p.TimeWindowID != nil {
+			t.TimeWindowID = p.TimeWindowID
+		}
+	}
+
+	if to != "" {
+		t.State = to
+		t.StateUpdatedAt = nowString()
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+// Drivers
+
+func (s *Server) routeDrivers(w http.ResponseWriter, r *http.Request, seg []string, id int) {
+	if len(seg) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			f := filter{r.URL.Query()}
+			var items []model.Person
+			for _, did := range sortedIDs(s.drivers) {
+				d := s.drivers[did]
+				if f.keyword(d.Name, d.Username, d.ContactNumber) && f.archived(d.Archived) {
+					items = append(items, *d)
+				}
+			}
+			page, meta := paginate(items, r.URL.Query())
+			writeJSON(w, http.StatusOK, map[string]interface{}{"drivers": page, "meta": meta})
+		case http.MethodPost:
+			var req model.DriverRequest
+			if !decodeBody(w, r, &req) {
+				return
+			}
+			p := req.Driver
+			if p == nil || p.Name == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Validation failed", map[string][]string{"name": {"can't be blank"}})
+				return
+			}
+			d := &model.Person{
+				ID: s.newID(), Name: p.Name, ContactNumber: p.ContactNumber, Username: p.Username,
+				HasPassword: p.Password != "", License: p.License, Nric: p.Nric, Dob: p.Dob,
+				IsAttendant: p.IsAttendant, Skills: p.SkillList, Address: p.AddressAttributes,
+				CustomFields: p.CustomFields,
+			}
+			if p.DefaultVehicleID != nil {
+				d.DefaultVehicle = s.vehicles[*p.DefaultVehicleID]
+			}
+			s.drivers[d.ID] = d
+			writeJSON(w, http.StatusCreated, model.DriverResponse{Driver: *d})
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	d, ok := s.drivers[id]
+	if !ok {
+		notFound(w, "Driver", id)
+		return
+	}
+	switch {
+	case len(seg) == 2 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, model.DriverResponse{Driver: *d})
+	case len(seg) == 2 && r.Method == http.MethodPut:
+		var req model.DriverUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		p := req.Driver
+		if p == nil {
+			p = &model.DriverUpdateParams{}
+		}
+		setIf(&d.Name, p.Name)
+		setIf(&d.ContactNumber, p.ContactNumber)
+		setIf(&d.Username, p.Username)
+		setIf(&d.License, p.License)
+		setIf(&d.Nric, p.Nric)
+		setIf(&d.Dob, p.Dob)
+		if p.Password != nil {
+			d.HasPassword = *p.Password != ""
+		}
+		if p.SkillList != nil {
+			d.Skills = *p.SkillList
+		}
+		if p.DefaultVehicleID != nil {
+			d.DefaultVehicle = s.vehicles[*p.DefaultVehicleID]
+		}
+		writeJSON(w, http.StatusOK, model.DriverResponse{Driver: *d})
+	case len(seg) == 2 && r.Method == http.MethodDelete:
+		delete(s.drivers, id)
+		w.WriteHeader(http.StatusNoContent)
+	case len(seg) == 3 && r.Method == http.MethodPut && (seg[2] == "archive" || seg[2] == "unarchive"):
+		d.Archived = seg[2] == "archive"
+		writeJSON(w, http.StatusOK, model.DriverResponse{Driver: *d})
+	default:
+		writeError(w, http.StatusNotFound, "Not Found", nil)
+	}
+}
+
+func setIf(dst *string, v *string) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+// Vehicles
+
+func (s *Server) routeVehicles(w http.ResponseWriter, r *http.Request, seg []string, id int) {
+	if len(seg) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			f := filter{r.URL.Query()}
+			var items []model.Vehicle
+			for _, vid := range sortedIDs(s.vehicles) {
+				v := s.vehicles[vid]
+				if f.keyword(v.PlateNumber, v.Model) && f.str("category", v.Category) && f.str("status", v.Status) {
+					items = append(items, *v)
+				}
+			}
+			page, meta := paginate(items, r.URL.Query())
+			writeJSON(w, http.StatusOK, map[string]interface{}{"vehicles": page, "meta": meta})
+		case http.MethodPost:
+			var req model.VehicleRequest
+			if !decodeBody(w, r, &req) {
+				return
+			}
+			p := req.Vehicle
+			if p == nil || p.PlateNumber == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Validation failed", map[string][]string{"plate_number": {"can't be blank"}})
+				return
+			}
+			v := &model.Vehicle{
+				ID: s.newID(), PlateNumber: p.PlateNumber, Status: p.Status, CargoLoad: p.CargoLoad,
+				Model: p.Model, Category: p.Category, OwnershipDate: p.OwnershipDate,
+				RegistrationDate: p.RegistrationDate, InsuranceExpiry: p.InsuranceExpiry,
+				TaxExpiry: p.TaxExpiry, Skills: p.SkillList, CustomFields: p.CustomFields,
+			}
+			s.vehicles[v.ID] = v
+			writeJSON(w, http.StatusCreated, model.VehicleResponse{Vehicle: *v})
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	v, ok := s.vehicles[id]
+	if !ok {
+		notFound(w, "Vehicle", id)
+		return
+	}
+	switch {
+	case len(seg) == 2 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, model.VehicleResponse{Vehicle: *v})
+	case len(seg) == 2 && r.Method == http.MethodPut:
+		var req model.VehicleUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		p := req.Vehicle
+		if p == nil {
+			p = &model.VehicleUpdateParams{}
+		}
+		setIf(&v.PlateNumber, p.PlateNumber)
+		setIf(&v.Status, p.Status)
+		setIf(&v.Model, p.Model)
+		setIf(&v.Category, p.Category)
+		setIf(&v.OwnershipDate, p.OwnershipDate)
+		setIf(&v.RegistrationDate, p.RegistrationDate)
+		setIf(&v.InsuranceExpiry, p.InsuranceExpiry)
+		setIf(&v.TaxExpiry, p.TaxExpiry)
+		if p.CargoLoad != nil {
+			v.CargoLoad = *p.CargoLoad
+		}
+		if p.SkillList != nil {
+			v.Skills = *p.SkillList
+		}
+		writeJSON(w, http.StatusOK, model.VehicleResponse{Vehicle: *v})
+	case len(seg) == 2 && r.Method == http.MethodDelete:
+		delete(s.vehicles, id)
+		w.WriteHeader(http.StatusNoContent)
+	case len(seg) == 3 && r.Method == http.MethodPut && (seg[2] == "archive" || seg[2] == "unarchive"):
+		v.Status = "active"
+		if seg[2] == "archive" {
+			v.Status = "archived"
+		}
+		writeJSON(w, http.StatusOK, model.VehicleResponse{Vehicle: *v})
+	default:
+		writeError(w, http.StatusNotFound, "Not Found", nil)
+	}
+}
+
+// Customers
+
+// addCustomer stores a customer. Callers hold s.mu.
+func (s *Server) addCustomer(c model.Customer) *model.CustomerDetail {
+	if c.ID == 0 {
+		c.ID = s.newID()
+	}
+	detail := &model.CustomerDetail{Customer: c}
+	if c.BillingAccountsAttributes != nil {
+		ba := *c.BillingAccountsAttributes
+		ba.ID = s.newID()
+		detail.BillingAccounts = []model.BillingAccount{ba}
+		detail.Customer.BillingAccountsAttributes = nil
+	}
+	s.customers[c.ID] = detail
+	return detail
+}
+
+func (s *Server) routeCustomers(w http.ResponseWriter, r *http.Request, seg []string, id int) {
+	if len(seg) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			f := filter{r.URL.Query()}
+			var items []model.Customer
+			for _, cid := range sortedIDs(s.customers) {
+				c := s.customers[cid]
+				if f.keyword(c.Name, c.Email, c.ContactPerson) && f.archived(c.Archived) {
+					items = append(items, c.Customer)
+				}
+			}
+			page, meta := paginate(items, r.URL.Query())
+			writeJSON(w, http.StatusOK, map[string]interface{}{"customers": page, "meta": meta})
+		case http.MethodPost:
+			var c model.Customer
+			if !decodeBody(w, r, &c) {
+				return
+			}
+			if c.Name == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Validation failed", map[string][]string{"name": {"can't be blank"}})
+				return
+			}
+			c.ID = 0
+			detail := s.addCustomer(c)
+			writeJSON(w, http.StatusCreated, map[string]interface{}{"customer": detail.Customer})
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	c, ok := s.customers[id]
+	if !ok || len(seg) != 2 {
+		notFound(w, "Customer", id)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, c)
+	case http.MethodPut:
+		var u model.Customer
+		if !decodeBody(w, r, &u) {
+			return
+		}
+		if u.Name != "" {
+			c.Name = u.Name
+		}
+		if u.Email != "" {
+			c.Email = u.Email
+		}
+		if u.ContactPerson != "" {
+			c.ContactPerson = u.ContactPerson
+		}
+		if u.ContactNumber != "" {
+			c.ContactNumber = u.ContactNumber
+		}
+		writeJSON(w, http.StatusOK, c.Customer)
+	case http.MethodDelete:
+		delete(s.customers, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// Accounts
+
+func (s *Server) routeAccounts(w http.ResponseWriter, r *http.Request, seg []string, id int) {
+	if len(seg) == 1 {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w)
+			return
+		}
+		var a account.Account
+		if !decodeBody(w, r, &a) {
+			return
+		}
+		a.ID = int64(s.newID())
+		s.accounts[int(a.ID)] = &a
+		writeJSON(w, http.StatusCreated, a)
+		return
+	}
+
+	a, ok := s.accounts[id]
+	if !ok {
+		notFound(w, "Account", id)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, a)
+	case http.MethodPut:
+		var u account.Account
+		if !decodeBody(w, r, &u) {
+			return
+		}
+		u.ID = a.ID
+		*a = u
+		writeJSON(w, http.StatusOK, a)
+	case http.MethodDelete:
+		delete(s.accounts, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func nowString() string {
+	return time.Now().UTC().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,388 @@
+// Package versafleettest runs an in-memory fake of the VersaFleet API for tests.
+//
+// The fake is stateful: jobs, tasks, drivers, vehicles, customers and accounts
+// created through it can be read back, listed with pagination and filters, and
+// driven through the task lifecycle. Errors are returned in the API's error shape,
+// so they decode into *client.APIError, and latency, 429s and 5xx responses can be
+// injected to exercise retry and rate-limit handling.
+//
+//	srv := versafleettest.NewServer()
+//	defer srv.Close()
+//	c := client.New(srv.Config())
+package versafleettest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/account"
+	"github.com/Willias7788/go-versafleet-sdk/config"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+)
+
+// Credentials accepted by the fake
+const (
+	ClientID     = "test-client-id"
+	ClientSecret = "test-client-secret"
+	AccessToken  = "test-access-token"
+)
+
+// Fault is an injected failure
+type Fault struct {
+	Status     int           // Response status, e.g. 429 or 503
+	Count      int           // Number of matching requests to fail; 0 means every matching request
+	Method     string        // Only match this method, if set
+	PathPrefix string        // Only match paths with this prefix, if set
+	RetryAfter time.Duration // Sent as Retry-After (seconds) when set
+}
+
+func (f *Fault) matches(r *http.Request) bool {
+	if f.Method != "" && f.Method != r.Method {
+		return false
+	}
+	return f.PathPrefix == "" || strings.HasPrefix(r.URL.Path, f.PathPrefix)
+}
+
+// Server is a fake VersaFleet API backed by an httptest.Server
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	nextID    int
+	requestN  int
+	latency   time.Duration
+	faults    []*Fault
+	requests  []*http.Request
+	jobs      map[int]*model.Job
+	tasks     map[int]*model.Task
+	drivers   map[int]*model.Person
+	vehicles  map[int]*model.Vehicle
+	customers map[int]*model.CustomerDetail
+	accounts  map[int]*account.Account
+}
+
+// NewServer starts a fake API server. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		nextID:    1000,
+		jobs:      make(map[int]*model.Job),
+		tasks:     make(map[int]*model.Task),
+		drivers:   make(map[int]*model.Person),
+		vehicles:  make(map[int]*model.Vehicle),
+		customers: make(map[int]*model.CustomerDetail),
+		accounts:  make(map[int]*account.Account),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Config returns a client config pointing at the fake with valid credentials
+func (s *Server) Config() *config.Config {
+	return &config.Config{
+		BaseURL:      s.URL,
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		AuthMode:     config.AuthModeOAuth2,
+		TokenURL:     "/oauth/token",
+	}
+}
+
+// SetLatency delays every response by d
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// InjectFault makes matching requests fail. Faults are checked in the order they were injected.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns copies of the requests received so far, including token requests
+func (s *Server) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+func (s *Server) newID() int {
+	s.nextID++
+	return s.nextID
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requestN++
+	requestID := fmt.Sprintf("req_%d", s.requestN)
+	s.requests = append(s.requests, r.Clone(r.Context()))
+	latency := s.latency
+	fault := s.takeFault(r)
+	s.mu.Unlock()
+
+	w.Header().Set("X-Request-Id", requestID)
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fault != nil {
+		if fault.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(fault.RetryAfter.Round(time.Second)/time.Second)))
+		}
+		writeError(w, fault.Status, http.StatusText(fault.Status), nil)
+		return
+	}
+
+	if r.Method == http.MethodPost && r.URL.Path == "/oauth/token" {
+		s.token(w, r)
+		return
+	}
+	if !authorized(r) {
+		writeError(w, http.StatusUnauthorized, "Invalid credentials", nil)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.route(w, r, strings.Split(strings.Trim(r.URL.Path, "/"), "/"))
+}
+
+// takeFault returns the first matching fault, consuming one of its uses. Callers hold s.mu.
+func (s *Server) takeFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if !f.matches(r) {
+			continue
+		}
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "Malformed token request", nil)
+		return
+	}
+	if r.PostForm.Get("grant_type") != "client_credentials" ||
+		r.PostForm.Get("client_id") != ClientID || r.PostForm.Get("client_secret") != ClientSecret {
+		writeError(w, http.StatusUnauthorized, "Invalid client credentials", nil)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": AccessToken,
+		"token_type":   "Bearer",
+		"expires_in":   7200,
+		"created_at":   time.Now().Unix(),
+	})
+}
+
+// authorized accepts either the bearer token or query parameter credentials
+func authorized(r *http.Request) bool {
+	if r.Header.Get("Authorization") == "Bearer "+AccessToken {
+		return true
+	}
+	q := r.URL.Query()
+	return q.Get("client_id") == ClientID && q.Get("client_secret") == ClientSecret
+}
+
+// route dispatches on the path segments. Callers hold s.mu.
+func (s *Server) route(w http.ResponseWriter, r *http.Request, seg []string) {
+	if len(seg) > 0 && seg[0] == "v2" {
+		seg = seg[1:]
+	}
+	if len(seg) == 0 {
+		writeError(w, http.StatusNotFound, "Not Found", nil)
+		return
+	}
+
+	var id int
+	if len(seg) > 1 {
+		n, err := strconv.Atoi(seg[1])
+		if err != nil {
+			writeError(w, http.StatusNotFound, "Not Found", nil)
+			return
+		}
+		id = n
+	}
+
+	switch seg[0] {
+	case "jobs":
+		s.routeJobs(w, r, seg, id)
+	case "tasks":
+		s.routeTasks(w, r, seg, id)
+	case "drivers":
+		s.routeDrivers(w, r, seg, id)
+	case "vehicles":
+		s.routeVehicles(w, r, seg, id)
+	case "customers":
+		s.routeCustomers(w, r, seg, id)
+	case "accounts":
+		s.routeAccounts(w, r, seg, id)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found", nil)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the API's error shape, which decodes into client.APIError
+func writeError(w http.ResponseWriter, status int, message string, errs interface{}) {
+	body := map[string]interface{}{
+		"message":    message,
+		"request_id": w.Header().Get("X-Request-Id"),
+	}
+	if errs != nil {
+		body["errors"] = errs
+	}
+	writeJSON(w, status, body)
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Malformed JSON body", map[string]string{"body": err.Error()})
+		return false
+	}
+	return true
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed", nil)
+}
+
+func notFound(w http.ResponseWriter, kind string, id int) {
+	writeError(w, http.StatusNotFound, fmt.Sprintf("Couldn't find %s with 'id'=%d", kind, id), nil)
+}
+
+// sortedIDs returns map keys in ascending order, so listings are stable
+func sortedIDs[V any](m map[int]V) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// paginate applies page/per_page/order_by to items. meta.total is the number of pages, as model.Meta expects.
+func paginate[T any](items []T, q url.Values) ([]T, *model.Meta) {
+	if strings.EqualFold(q.Get("order_by"), "desc") {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if perPage < 1 {
+		perPage = 20
+	}
+
+	totalPages := (len(items) + perPage - 1) / perPage
+	meta := &model.Meta{TotalPages: totalPages, CurrentPage: page, PerPage: perPage}
+
+	start := (page - 1) * perPage
+	if start >= len(items) {
+		return []T{}, meta
+	}
+	end := min(start+perPage, len(items))
+	return items[start:end], meta
+}
+
+// filter holds the common list filters from the query string
+type filter struct {
+	q url.Values
+}
+
+func (f filter) keyword(fields ...string) bool {
+	kw := strings.ToLower(f.q.Get("keyword"))
+	if kw == "" {
+		return true
+	}
+	for _, v := range fields {
+		if strings.Contains(strings.ToLower(v), kw) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f filter) archived(archived bool) bool {
+	v := f.q.Get("archived")
+	return v == "" || v == strconv.FormatBool(archived)
+}
+
+func (f filter) str(key, value string) bool {
+	v := f.q.Get(key)
+	return v == "" || v == value
+}
+
+func (f filter) int(key string, value int) bool {
+	v := f.q.Get(key)
+	return v == "" || v == strconv.Itoa(value)
+}
+
+// timeRange checks a "YYYY-MM-DD HH:MM:SS" (or RFC 3339) value against from/to_datetime and date
+func (f filter) timeRange(value string) bool {
+	t, ok := parseTime(value)
+	if from := f.q.Get("from_datetime"); from != "" {
+		ft, fok := parseTime(from)
+		if !ok || (fok && t.Before(ft)) {
+			return false
+		}
+	}
+	if to := f.q.Get("to_datetime"); to != "" {
+		tt, tok := parseTime(to)
+		if !ok || (tok && t.After(tt)) {
+			return false
+		}
+	}
+	if date := f.q.Get("date"); date != "" {
+		if !ok || t.Format("2006-01-02") != date {
+			return false
+		}
+	}
+	return true
+}
+
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02 15:04:05", time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,306 @@
+package versafleettest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/client"
+	"github.com/Willias7788/go-versafleet-sdk/customers"
+	"github.com/Willias7788/go-versafleet-sdk/drivers"
+	"github.com/Willias7788/go-versafleet-sdk/jobs"
+	"github.com/Willias7788/go-versafleet-sdk/model"
+	"github.com/Willias7788/go-versafleet-sdk/rate"
+	"github.com/Willias7788/go-versafleet-sdk/tasks"
+	"github.com/Willias7788/go-versafleet-sdk/vehicles"
+	"github.com/Willias7788/go-versafleet-sdk/versafleettest"
+)
+
+// newClient is a client for srv with a generous limiter and fast retries, so tests don't wait
+func newClient(srv *versafleettest.Server, opts ...client.Option) *client.Client {
+	policy := client.DefaultRetryPolicy()
+	policy.MinWait, policy.MaxWait = time.Millisecond, 5*time.Millisecond
+	opts = append([]client.Option{
+		client.WithLimiter(rate.New(rate.Params{RPS: 1000, Burst: 100})),
+		client.WithRetryPolicy(policy),
+	}, opts...)
+	return client.New(srv.Config(), opts...)
+}
+
+// apiError asserts that err is a *client.APIError with the given status
+func apiError(t *testing.T, err error, status int) *client.APIError {
+	t.Helper()
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error %v (%T) is not a *client.APIError", err, err)
+	}
+	if apiErr.StatusCode != status {
+		t.Fatalf("status = %d, want %d (%v)", apiErr.StatusCode, status, apiErr)
+	}
+	return apiErr
+}
+
+func TestCustomersCRUD(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	svc := customers.New(newClient(srv))
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, &model.Customer{Name: "ACME", Email: "ops@acme.test"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	id := strconv.Itoa(created.ID)
+
+	got, err := svc.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Name != "ACME" || got.Email != "ops@acme.test" {
+		t.Errorf("Get = %+v", got.Customer)
+	}
+
+	if _, err := svc.Update(ctx, id, &model.Customer{Name: "ACME Pte Ltd"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got, _ = svc.Get(ctx, id); got.Name != "ACME Pte Ltd" || got.Email != "ops@acme.test" {
+		t.Errorf("after Update = %+v", got.Customer)
+	}
+
+	if err := svc.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	_, err = svc.Get(ctx, id)
+	apiError(t, err, http.StatusNotFound)
+
+	// Validation failures come back as 422 with the field details
+	_, err = svc.Create(ctx, &model.Customer{})
+	if apiErr := apiError(t, err, http.StatusUnprocessableEntity); apiErr.Errors == nil {
+		t.Error("422 has no error details")
+	}
+}
+
+func TestVehiclesCRUD(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	svc := vehicles.New(newClient(srv))
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, &model.VehicleCreateParams{PlateNumber: "SGX1234A", Category: "van"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	id := strconv.Itoa(created.ID)
+
+	updated, err := svc.Update(ctx, id, &model.VehicleUpdateParams{Model: strPtr("Transit")})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.PlateNumber != "SGX1234A" || updated.Model != "Transit" {
+		t.Errorf("Update = %+v", updated)
+	}
+
+	archived, err := svc.Archive(ctx, id)
+	if err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	if archived.Status != "archived" {
+		t.Errorf("status after Archive = %q", archived.Status)
+	}
+
+	if err := svc.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	_, err = svc.Get(ctx, id)
+	apiError(t, err, http.StatusNotFound)
+}
+
+func TestJobsCreateGetDelete(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	customer := srv.AddCustomer(model.Customer{Name: "ACME"})
+	svc := jobs.New(newClient(srv))
+	ctx := context.Background()
+
+	job, err := svc.Create(ctx, &model.JobParams{
+		JobType:    "delivery",
+		CustomerID: customer.ID,
+		TasksAttributes: []model.TaskParams{
+			{TrackingID: "TRK-1"},
+			{TrackingID: "TRK-2"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	id := strconv.Itoa(job.ID)
+
+	got, err := svc.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Customer.Name != "ACME" || len(got.Tasks) != 2 || got.State != model.JobStateUnassigned {
+		t.Errorf("Get = customer %q, %d tasks, state %s", got.Customer.Name, len(got.Tasks), got.State)
+	}
+
+	if err := svc.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := srv.Task(got.Tasks[0].ID); ok {
+		t.Error("deleting the job left its tasks behind")
+	}
+}
+
+func TestPaginationMeta(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	for i := 0; i < 45; i++ {
+		srv.AddDriver(model.Person{Name: fmt.Sprintf("Driver %02d", i)})
+	}
+	svc := drivers.New(newClient(srv))
+
+	opts := &model.DriverListOptions{CommonListOptions: model.CommonListOptions{ListOptions: model.ListOptions{PerPage: 20}}}
+	var sizes []int
+	for page, err := range svc.List(context.Background(), opts).Pages() {
+		if err != nil {
+			t.Fatalf("Pages: %v", err)
+		}
+		want := model.Meta{TotalPages: 3, CurrentPage: len(sizes) + 1, PerPage: 20}
+		if *page.Meta != want {
+			t.Errorf("page %d meta = %+v, want %+v", len(sizes)+1, *page.Meta, want)
+		}
+		sizes = append(sizes, len(page.Items))
+	}
+	if fmt.Sprint(sizes) != "[20 20 5]" {
+		t.Errorf("page sizes = %v, want [20 20 5]", sizes)
+	}
+}
+
+func TestListFilters(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	acme := srv.AddCustomer(model.Customer{Name: "ACME"})
+	other := srv.AddCustomer(model.Customer{Name: "Other"})
+	srv.AddJob(model.JobParams{JobType: "delivery", CustomerID: acme.ID, Remarks: "fragile"})
+	srv.AddJob(model.JobParams{JobType: "delivery", CustomerID: acme.ID})
+	srv.AddJob(model.JobParams{JobType: "pickup", CustomerID: other.ID})
+	svc := jobs.New(newClient(srv))
+
+	tests := []struct {
+		name string
+		opts *model.JobListOptions
+		want int
+	}{
+		{"none", &model.JobListOptions{}, 3},
+		{"customer", &model.JobListOptions{CustomerID: &acme.ID}, 2},
+		{"keyword", &model.JobListOptions{CommonListOptions: model.CommonListOptions{Keyword: strPtr("fragile")}}, 1},
+		{"state", &model.JobListOptions{CommonListOptions: model.CommonListOptions{State: strPtr(string(model.JobStateCompleted))}}, 0},
+		{"archived", &model.JobListOptions{CommonListOptions: model.CommonListOptions{Archived: boolPtr(false)}}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.List(context.Background(), tt.opts).CollectAll(context.Background(), 0)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("got %d jobs, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskLifecycle(t *testing.T) {
+	srv := versafleettest.NewServer()
+	defer srv.Close()
+	driver := srv.AddDriver(model.Person{Name: "Ah Tan"})
+	job := srv.AddJob(model.JobParams{JobType: "delivery", CustomerID: 1, TasksAttributes: []model.TaskParams{{TrackingID: "TRK-1"}}})
+	id := strconv.Itoa(job.Tasks[0].ID)
+	svc := tasks.New(newClient(srv))
+	ctx := context.Background()
+
+	steps := []struct {
+		name string
+		do   func() (*model.Task, error)
+		want model.TaskState
+	}{
+		{"assign", func() (*model.Task, error) { return svc.Assign(ctx, id, &model.TaskAssignParams{DriverID: &driver.ID}) }, model.TaskStateAssigned},
+		{"start", func() (*model.Task, error) { return svc.Start(ctx, id) }, model.TaskStateStarted},
+		{"complete", func() (*model.Task, error) {
+			return svc.Complete(ctx, id, &model.TaskCompleteParams{RecipientName: "Mr Lim"})
+		}, model.TaskStateSuccessful},
+	}
+	for _, step := range steps {
+		task, err := step.do()
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if task.State != step.want {
+			t.Fatalf("after %s state = %s, want %s", step.name, task.State, step.want)
+		}
+	}
+	if stored, _ := srv.Task(job.Tasks[0].ID); stored.RecipientName == nil || *stored.RecipientName != "Mr Lim" {
+		t.Errorf("recipient not stored: %+v", stored.RecipientName)
+	}
+
+	// A successful task is final, and the fake rejects moving it like the API does
+	_, err := svc.Cancel(ctx, id)
+	apiError(t, err, http.StatusUnprocessableEntity)
+}
+
+func TestInjectedFaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		fault  versafleettest.Fault
+		policy func(*client.RetryPolicy)
+		ok     bool // whether the call succeeds after retrying
+	}{
+		{"429 without retries", versafleettest.Fault{Status: http.StatusTooManyRequests, RetryAfter: time.Second}, func(p *client.RetryPolicy) { p.MaxAttempts = 1 }, false},
+		{"500 without retries", versafleettest.Fault{Status: http.StatusInternalServerError}, func(p *client.RetryPolicy) { p.MaxAttempts = 1 }, false},
+		{"503 until retries run out", versafleettest.Fault{Status: http.StatusServiceUnavailable}, nil, false},
+		{"502 twice, then retried", versafleettest.Fault{Status: http.StatusBadGateway, Count: 2}, nil, true},
+		{"non-retryable 501", versafleettest.Fault{Status: http.StatusNotImplemented, Count: 1}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := versafleettest.NewServer()
+			defer srv.Close()
+			customer := srv.AddCustomer(model.Customer{Name: "ACME"})
+
+			policy := client.DefaultRetryPolicy()
+			policy.MinWait, policy.MaxWait = time.Millisecond, 5*time.Millisecond
+			if tt.policy != nil {
+				tt.policy(&policy)
+			}
+			svc := customers.New(newClient(srv, client.WithRetryPolicy(policy)))
+
+			tt.fault.PathPrefix = "/customers"
+			srv.InjectFault(tt.fault)
+			got, err := svc.Get(context.Background(), strconv.Itoa(customer.ID))
+			if tt.ok {
+				if err != nil {
+					t.Fatalf("Get: %v", err)
+				}
+				if got.Name != "ACME" {
+					t.Errorf("Get = %+v", got.Customer)
+				}
+				return
+			}
+
+			apiErr := apiError(t, err, tt.fault.Status)
+			if apiErr.RequestID == "" {
+				t.Error("APIError has no request ID")
+			}
+			if apiErr.Message != http.StatusText(tt.fault.Status) {
+				t.Errorf("message = %v, want %q", apiErr.Message, http.StatusText(tt.fault.Status))
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string { return &s }
+func boolPtr(b bool) *bool    { return &b }
@@ -0,0 +1,96 @@
+package webhooks
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// DedupStore remembers which event IDs have been delivered
+type DedupStore interface {
+	// MarkSeen records id and reports whether it had already been recorded.
+	// It must be atomic, so concurrent deliveries of the same event see exactly one false.
+	MarkSeen(ctx context.Context, id string) (seen bool, err error)
+	// Forget removes id, so a delivery whose processing failed can be processed again on retry
+	Forget(ctx context.Context, id string) error
+}
+
+// MemoryDedupStore is an in-memory DedupStore that keeps at most a fixed number
+// of IDs, each for a limited time, evicting the least recently seen first.
+type MemoryDedupStore struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	entries  map[string]*list.Element
+	order    *list.List // Front is most recent
+	now      func() time.Time
+}
+
+type dedupEntry struct {
+	id     string
+	seenAt time.Time
+}
+
+// NewMemoryDedupStore creates a store holding up to capacity IDs for ttl each.
+// ttl <= 0 keeps IDs until they are evicted by capacity.
+func NewMemoryDedupStore(capacity int, ttl time.Duration) *MemoryDedupStore {
+	if capacity <= 0 {
+		capacity = 10000
+	}
+	return &MemoryDedupStore{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (s *MemoryDedupStore) MarkSeen(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.expire(now)
+
+	if el, ok := s.entries[id]; ok {
+		// Retries keep the ID alive; this also keeps the list ordered by seenAt for expire
+		el.Value.(*dedupEntry).seenAt = now
+		s.order.MoveToFront(el)
+		return true, nil
+	}
+
+	s.entries[id] = s.order.PushFront(&dedupEntry{id: id, seenAt: now})
+	for s.order.Len() > s.capacity {
+		s.remove(s.order.Back())
+	}
+	return false, nil
+}
+
+func (s *MemoryDedupStore) Forget(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.entries[id]; ok {
+		s.remove(el)
+	}
+	return nil
+}
+
+// expire drops entries older than the TTL, oldest first
+func (s *MemoryDedupStore) expire(now time.Time) {
+	if s.ttl <= 0 {
+		return
+	}
+	for el := s.order.Back(); el != nil; el = s.order.Back() {
+		if now.Sub(el.Value.(*dedupEntry).seenAt) < s.ttl {
+			return
+		}
+		s.remove(el)
+	}
+}
+
+func (s *MemoryDedupStore) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.entries, el.Value.(*dedupEntry).id)
+}
@@ -0,0 +1,184 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Willias7788/go-versafleet-sdk/model"
+)
+
+const (
+	EventTypeJobCreated   EventType = "job.created"
+	EventTypeJobUpdated   EventType = "job.updated"
+	EventTypeJobAssigned  EventType = "job.assigned"
+	EventTypeJobStarted   EventType = "job.started"
+	EventTypeJobCompleted EventType = "job.completed"
+	EventTypeJobFailed    EventType = "job.failed"
+	EventTypeJobCancelled EventType = "job.cancelled"
+	EventTypeJobDeleted   EventType = "job.deleted"
+
+	EventTypeTaskCreated     EventType = "task.created"
+	EventTypeTaskUpdated     EventType = "task.updated"
+	EventTypeTaskAssigned    EventType = "task.assigned"
+	EventTypeTaskUnassigned  EventType = "task.unassigned"
+	EventTypeTaskAccepted    EventType = "task.accepted"
+	EventTypeTaskDeclined    EventType = "task.declined"
+	EventTypeTaskStarted     EventType = "task.started"
+	EventTypeTaskArrived     EventType = "task.arrived"
+	EventTypeTaskCompleted   EventType = "task.completed"
+	EventTypeTaskFailed      EventType = "task.failed"
+	EventTypeTaskCancelled   EventType = "task.cancelled"
+	EventTypeTaskRescheduled EventType = "task.rescheduled"
+	EventTypeTaskDeleted     EventType = "task.deleted"
+
+	EventTypeDriverCreated  EventType = "driver.created"
+	EventTypeDriverUpdated  EventType = "driver.updated"
+	EventTypeDriverArchived EventType = "driver.archived"
+
+	EventTypeVehicleCreated  EventType = "vehicle.created"
+	EventTypeVehicleUpdated  EventType = "vehicle.updated"
+	EventTypeVehicleArchived EventType = "vehicle.archived"
+
+	EventTypeCustomerCreated EventType = "customer.created"
+	EventTypeCustomerUpdated EventType = "customer.updated"
+)
+
+// JobEvent is the payload of job.* events
+type JobEvent struct {
+	Job           model.Job      `json:"job"`
+	PreviousState model.JobState `json:"previous_state,omitempty"`
+}
+
+// TaskEvent is the payload of task.* events
+type TaskEvent struct {
+	Task          model.Task      `json:"task"`
+	PreviousState model.TaskState `json:"previous_state,omitempty"`
+}
+
+// DriverEvent is the payload of driver.* events
+type DriverEvent struct {
+	Driver model.Person `json:"driver"`
+}
+
+// VehicleEvent is the payload of vehicle.* events
+type VehicleEvent struct {
+	Vehicle model.Vehicle `json:"vehicle"`
+}
+
+// CustomerEvent is the payload of customer.* events
+type CustomerEvent struct {
+	Customer model.Customer `json:"customer"`
+}
+
+// payloadTypes maps each known event type to a constructor for its payload
+var payloadTypes = map[EventType]func() interface{}{}
+
+func init() {
+	register := func(fn func() interface{}, types ...EventType) {
+		for _, t := range types {
+			payloadTypes[t] = fn
+		}
+	}
+	register(func() interface{} { return &JobEvent{} },
+		EventTypeJobCreated, EventTypeJobUpdated, EventTypeJobAssigned, EventTypeJobStarted,
+		EventTypeJobCompleted, EventTypeJobFailed, EventTypeJobCancelled, EventTypeJobDeleted)
+	register(func() interface{} { return &TaskEvent{} },
+		EventTypeTaskCreated, EventTypeTaskUpdated, EventTypeTaskAssigned, EventTypeTaskUnassigned,
+		EventTypeTaskAccepted, EventTypeTaskDeclined, EventTypeTaskStarted, EventTypeTaskArrived,
+		EventTypeTaskCompleted, EventTypeTaskFailed, EventTypeTaskCancelled, EventTypeTaskRescheduled,
+		EventTypeTaskDeleted)
+	register(func() interface{} { return &DriverEvent{} },
+		EventTypeDriverCreated, EventTypeDriverUpdated, EventTypeDriverArchived)
+	register(func() interface{} { return &VehicleEvent{} },
+		EventTypeVehicleCreated, EventTypeVehicleUpdated, EventTypeVehicleArchived)
+	register(func() interface{} { return &CustomerEvent{} },
+		EventTypeCustomerCreated, EventTypeCustomerUpdated)
+}
+
+// EventTypes returns every event type the SDK knows how to decode, sorted
+func EventTypes() []EventType {
+	types := make([]EventType, 0, len(payloadTypes))
+	for t := range payloadTypes {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// IsKnown reports whether the SDK has a typed payload for this event type
+func (t EventType) IsKnown() bool {
+	_, ok := payloadTypes[t]
+	return ok
+}
+
+// DecodeData decodes the event payload into its concrete type:
+// *JobEvent, *TaskEvent, *DriverEvent, *VehicleEvent or *CustomerEvent.
+// Unknown event types are returned as the raw json.RawMessage rather than rejected.
+func (e *Event) DecodeData() (interface{}, error) {
+	newPayload, ok := payloadTypes[e.Type]
+	if !ok {
+		return e.Data, nil
+	}
+	payload := newPayload()
+	if err := json.Unmarshal(e.Data, payload); err != nil {
+		return nil, fmt.Errorf("webhooks: failed to decode %s payload: %w", e.Type, err)
+	}
+	return payload, nil
+}
+
+// TaskEvent decodes the payload of a task.* event
+func (e *Event) TaskEvent() (*TaskEvent, error) {
+	var payload TaskEvent
+	if err := e.decodeAs("task.", &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// JobEvent decodes the payload of a job.* event
+func (e *Event) JobEvent() (*JobEvent, error) {
+	var payload JobEvent
+	if err := e.decodeAs("job.", &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// DriverEvent decodes the payload of a driver.* event
+func (e *Event) DriverEvent() (*DriverEvent, error) {
+	var payload DriverEvent
+	if err := e.decodeAs("driver.", &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// VehicleEvent decodes the payload of a vehicle.* event
+func (e *Event) VehicleEvent() (*VehicleEvent, error) {
+	var payload VehicleEvent
+	if err := e.decodeAs("vehicle.", &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// CustomerEvent decodes the payload of a customer.* event
+func (e *Event) CustomerEvent() (*CustomerEvent, error) {
+	var payload CustomerEvent
+	if err := e.decodeAs("customer.", &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+func (e *Event) decodeAs(prefix string, payload interface{}) error {
+	if !strings.HasPrefix(string(e.Type), prefix) {
+		return fmt.Errorf("webhooks: %s is not a %s* event", e.Type, prefix)
+	}
+	if err := json.Unmarshal(e.Data, payload); err != nil {
+		return fmt.Errorf("webhooks: failed to decode %s payload: %w", e.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package webhooks_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/Willias7788/go-versafleet-sdk/model"
+	"github.com/Willias7788/go-versafleet-sdk/webhooks"
+)
+
+func TestEventTypesSorted(t *testing.T) {
+	types := webhooks.EventTypes()
+	if !slices.IsSorted(types) {
+		t.Errorf("EventTypes not sorted: %v", types)
+	}
+	for range 5 {
+		if again := webhooks.EventTypes(); !reflect.DeepEqual(again, types) {
+			t.Fatalf("EventTypes changed between calls: %v then %v", types, again)
+		}
+	}
+	for _, typ := range types {
+		if !typ.IsKnown() {
+			t.Errorf("%s listed but not known", typ)
+		}
+	}
+	if webhooks.EventType("route.optimised").IsKnown() {
+		t.Error("unlisted event type reported as known")
+	}
+}
+
+func TestDecodeData(t *testing.T) {
+	tests := []struct {
+		typ  webhooks.EventType
+		data string
+		want interface{}
+	}{
+		{webhooks.EventTypeJobCompleted, `{"job":{"id":1,"state":"completed"},"previous_state":"in_progress"}`,
+			&webhooks.JobEvent{Job: model.Job{ID: 1, State: model.JobStateCompleted}, PreviousState: model.JobStateInProgress}},
+		{webhooks.EventTypeTaskFailed, `{"task":{"id":2},"previous_state":"started"}`,
+			&webhooks.TaskEvent{Task: model.Task{ID: 2}, PreviousState: model.TaskStateStarted}},
+		{webhooks.EventTypeDriverArchived, `{"driver":{"id":3,"name":"Ah Tan"}}`,
+			&webhooks.DriverEvent{Driver: model.Person{ID: 3, Name: "Ah Tan"}}},
+		{webhooks.EventTypeVehicleUpdated, `{"vehicle":{"id":4,"plate_number":"SGX1234A"}}`,
+			&webhooks.VehicleEvent{Vehicle: model.Vehicle{ID: 4, PlateNumber: "SGX1234A"}}},
+		{webhooks.EventTypeCustomerCreated, `{"customer":{"id":5,"name":"ACME"}}`,
+			&webhooks.CustomerEvent{Customer: model.Customer{ID: 5, Name: "ACME"}}},
+		// Unknown types are passed through undecoded
+		{"route.optimised", `{"route":{"id":6}}`, json.RawMessage(`{"route":{"id":6}}`)},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.typ), func(t *testing.T) {
+			event := &webhooks.Event{Type: tt.typ, Data: json.RawMessage(tt.data)}
+			got, err := event.DecodeData()
+			if err != nil {
+				t.Fatalf("DecodeData: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeData = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeDataMalformed(t *testing.T) {
+	event := &webhooks.Event{Type: webhooks.EventTypeJobCreated, Data: json.RawMessage(`{"job":"not an object"}`)}
+	if _, err := event.DecodeData(); err == nil {
+		t.Error("DecodeData accepted a malformed job payload")
+	}
+}
+
+func TestTypedAccessors(t *testing.T) {
+	event := &webhooks.Event{Type: webhooks.EventTypeTaskCompleted, Data: json.RawMessage(`{"task":{"id":7}}`)}
+	task, err := event.TaskEvent()
+	if err != nil || task.Task.ID != 7 {
+		t.Errorf("TaskEvent = %+v, %v", task, err)
+	}
+	// Asking for the wrong kind of payload is an error, not an empty struct
+	if _, err := event.JobEvent(); err == nil {
+		t.Error("JobEvent decoded a task event")
+	}
+	if _, err := event.DriverEvent(); err == nil {
+		t.Error("DriverEvent decoded a task event")
+	}
+}
@@ -0,0 +1,208 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// DefaultMaxBodyBytes caps webhook bodies read by Handler
+const DefaultMaxBodyBytes = 1 << 20 // 1MB
+
+// HandlerFunc handles a verified webhook event
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// Handler is an http.Handler that verifies webhook deliveries and dispatches them
+// to callbacks registered per event type.
+//
+// Responses follow the callback outcome so VersaFleet retries correctly:
+// 200 when the callback succeeds (or nothing is registered for the event),
+// 500 when it returns an error, 401 for a missing or invalid signature,
+// 413 for an oversized body, 400 for a malformed or stale one, and 500 when
+// the secret provider or dedup store fails.
+// Duplicate deliveries (with Dedup set) are acknowledged with 200 without calling back.
+type Handler struct {
+	secrets  SecretProvider
+	handlers map[EventType]HandlerFunc
+	fallback HandlerFunc
+
+	// MaxBodyBytes caps the request body (default DefaultMaxBodyBytes)
+	MaxBodyBytes int64
+	// Tolerance, if set, rejects events whose created_at is further than this from now
+	Tolerance time.Duration
+	// Dedup, if set, acknowledges already delivered event IDs without dispatching them again
+	Dedup DedupStore
+	// OnSecretMatched, if set, is called with the secret that verified each delivery
+	OnSecretMatched func(r *http.Request, secret Secret)
+	// OnError, if set, is called with every rejected delivery or callback error
+	OnError func(r *http.Request, err error)
+}
+
+// NewHandler creates a webhook handler that verifies deliveries with secret
+func NewHandler(secret string) *Handler {
+	return NewHandlerWithSecrets(StaticSecret(secret))
+}
+
+// NewHandlerWithSecrets creates a webhook handler that accepts deliveries signed with
+// any secret from provider that is currently active
+func NewHandlerWithSecrets(provider SecretProvider) *Handler {
+	return &Handler{
+		secrets:      provider,
+		handlers:     make(map[EventType]HandlerFunc),
+		MaxBodyBytes: DefaultMaxBodyBytes,
+	}
+}
+
+// On registers the callback for an event type, replacing any earlier one
+func (h *Handler) On(t EventType, fn HandlerFunc) *Handler {
+	h.handlers[t] = fn
+	return h
+}
+
+// Fallback registers the callback for events without a specific callback
+func (h *Handler) Fallback(fn HandlerFunc) *Handler {
+	h.fallback = fn
+	return h
+}
+
+// OnTask registers a callback for a task.* event with its decoded payload
+func (h *Handler) OnTask(t EventType, fn func(ctx context.Context, e *TaskEvent) error) *Handler {
+	return h.On(t, func(ctx context.Context, event *Event) error {
+		payload, err := event.TaskEvent()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, payload)
+	})
+}
+
+// OnJob registers a callback for a job.* event with its decoded payload
+func (h *Handler) OnJob(t EventType, fn func(ctx context.Context, e *JobEvent) error) *Handler {
+	return h.On(t, func(ctx context.Context, event *Event) error {
+		payload, err := event.JobEvent()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, payload)
+	})
+}
+
+// OnDriver registers a callback for a driver.* event with its decoded payload
+func (h *Handler) OnDriver(t EventType, fn func(ctx context.Context, e *DriverEvent) error) *Handler {
+	return h.On(t, func(ctx context.Context, event *Event) error {
+		payload, err := event.DriverEvent()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, payload)
+	})
+}
+
+func (h *Handler) OnJobCreated(fn func(ctx context.Context, e *JobEvent) error) *Handler {
+	return h.OnJob(EventTypeJobCreated, fn)
+}
+
+func (h *Handler) OnJobUpdated(fn func(ctx context.Context, e *JobEvent) error) *Handler {
+	return h.OnJob(EventTypeJobUpdated, fn)
+}
+
+func (h *Handler) OnJobCompleted(fn func(ctx context.Context, e *JobEvent) error) *Handler {
+	return h.OnJob(EventTypeJobCompleted, fn)
+}
+
+func (h *Handler) OnTaskAssigned(fn func(ctx context.Context, e *TaskEvent) error) *Handler {
+	return h.OnTask(EventTypeTaskAssigned, fn)
+}
+
+func (h *Handler) OnTaskCompleted(fn func(ctx context.Context, e *TaskEvent) error) *Handler {
+	return h.OnTask(EventTypeTaskCompleted, fn)
+}
+
+func (h *Handler) OnTaskFailed(fn func(ctx context.Context, e *TaskEvent) error) *Handler {
+	return h.OnTask(EventTypeTaskFailed, fn)
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	maxBytes := h.MaxBodyBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBodyBytes
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+
+	var opts []ParseOption
+	if h.Tolerance > 0 {
+		opts = append(opts, WithTolerance(h.Tolerance))
+	}
+	if h.Dedup != nil {
+		opts = append(opts, WithDedup(h.Dedup))
+	}
+
+	event, secret, err := ParseWithSecrets(r, h.secrets, opts...)
+	if secret != nil && h.OnSecretMatched != nil {
+		h.OnSecretMatched(r, *secret)
+	}
+	if errors.Is(err, ErrDuplicate) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if err != nil {
+		h.reject(w, r, err)
+		return
+	}
+
+	fn, ok := h.handlers[event.Type]
+	if !ok {
+		fn = h.fallback
+	}
+	if fn == nil {
+		// Nothing to do; acknowledge so the delivery isn't retried
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := fn(r.Context(), event); err != nil {
+		if h.Dedup != nil && event.ID != "" {
+			// Let VersaFleet's retry be processed rather than acked as a duplicate
+			_ = h.Dedup.Forget(r.Context(), event.ID)
+		}
+		if h.OnError != nil {
+			h.OnError(r, err)
+		}
+		http.Error(w, "webhook handler failed", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// reject responds to a delivery that could not be parsed or verified
+func (h *Handler) reject(w http.ResponseWriter, r *http.Request, err error) {
+	if h.OnError != nil {
+		h.OnError(r, err)
+	}
+
+	var internalErr *internalError
+	var maxErr *http.MaxBytesError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &internalErr):
+		http.Error(w, "webhook handler unavailable", http.StatusInternalServerError)
+	case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignature):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.As(err, &maxErr):
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.Is(err, ErrStaleEvent):
+		http.Error(w, "malformed event", http.StatusBadRequest)
+	default:
+		http.Error(w, "failed to read event", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,91 @@
+package webhooks_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/webhooks"
+)
+
+const secret = "whsec_test"
+
+// eventBody is a delivery of an event of type typ created now
+func eventBody(id string, typ webhooks.EventType, data string) []byte {
+	body, _ := json.Marshal(webhooks.Event{ID: id, Type: typ, CreatedAt: time.Now().UTC().Format(time.RFC3339), Data: json.RawMessage(data)})
+	return body
+}
+
+// signedRequest is a POST of body signed with key, or unsigned if key is empty
+func signedRequest(body []byte, key string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+	if key != "" {
+		r.Header.Set(webhooks.SignatureHeader, webhooks.Sign(body, key))
+	}
+	return r
+}
+
+func serve(h http.Handler, r *http.Request) int {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestHandlerStatus(t *testing.T) {
+	taskBody := eventBody("evt-1", webhooks.EventTypeTaskCompleted, `{"task":{"id":7}}`)
+	tests := []struct {
+		name       string
+		setup      func(h *webhooks.Handler)
+		req        *http.Request
+		want       int
+		wantCalled bool
+	}{
+		{"callback succeeds", nil, signedRequest(taskBody, secret), http.StatusOK, true},
+		{"callback fails", func(h *webhooks.Handler) {
+			h.OnTaskCompleted(func(context.Context, *webhooks.TaskEvent) error { return errors.New("db down") })
+		}, signedRequest(taskBody, secret), http.StatusInternalServerError, false},
+		{"no callback registered", nil, signedRequest(eventBody("evt-2", webhooks.EventTypeJobCreated, `{"job":{"id":1}}`), secret), http.StatusOK, false},
+		{"fallback", func(h *webhooks.Handler) {
+			h.Fallback(func(context.Context, *webhooks.Event) error { return errors.New("unhandled") })
+		}, signedRequest(eventBody("evt-3", webhooks.EventTypeJobCreated, `{}`), secret), http.StatusInternalServerError, false},
+		{"undecodable payload", nil, signedRequest(eventBody("evt-4", webhooks.EventTypeTaskCompleted, `{"task":"oops"}`), secret), http.StatusInternalServerError, false},
+		{"missing signature", nil, signedRequest(taskBody, ""), http.StatusUnauthorized, false},
+		{"wrong secret", nil, signedRequest(taskBody, "whsec_other"), http.StatusUnauthorized, false},
+		{"malformed body", nil, signedRequest([]byte(`{"id":`), secret), http.StatusBadRequest, false},
+		{"body too large", func(h *webhooks.Handler) { h.MaxBodyBytes = 16 }, signedRequest(taskBody, secret), http.StatusRequestEntityTooLarge, false},
+		{"not a POST", nil, httptest.NewRequest(http.MethodGet, "/webhooks", nil), http.StatusMethodNotAllowed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			h := webhooks.NewHandler(secret).OnTaskCompleted(func(_ context.Context, e *webhooks.TaskEvent) error {
+				called = true
+				if e.Task.ID != 7 {
+					return fmt.Errorf("task %d, want 7", e.Task.ID)
+				}
+				return nil
+			})
+			var errs []error
+			h.OnError = func(_ *http.Request, err error) { errs = append(errs, err) }
+			if tt.setup != nil {
+				tt.setup(h)
+			}
+
+			if got := serve(h, tt.req); got != tt.want {
+				t.Errorf("status %d, want %d (errors %v)", got, tt.want, errs)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("callback called = %v, want %v", called, tt.wantCalled)
+			}
+			if failed := tt.want >= 400 && tt.want != http.StatusMethodNotAllowed; failed != (len(errs) > 0) {
+				t.Errorf("OnError got %v", errs)
+			}
+		})
+	}
+}
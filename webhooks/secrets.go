@@ -0,0 +1,55 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+)
+
+// Secret is a webhook signing secret, optionally limited to a validity window
+// so old and new secrets can overlap during rotation.
+type Secret struct {
+	ID        string // Identifies the secret in logs and metrics, never the value
+	Value     string
+	NotBefore time.Time // Zero means active immediately
+	ExpiresAt time.Time // Zero means never expires
+}
+
+// ActiveAt reports whether the secret may be used to verify a delivery at t
+func (s Secret) ActiveAt(t time.Time) bool {
+	if !s.NotBefore.IsZero() && t.Before(s.NotBefore) {
+		return false
+	}
+	if !s.ExpiresAt.IsZero() && !t.Before(s.ExpiresAt) {
+		return false
+	}
+	return true
+}
+
+// SecretProvider supplies the current set of signing secrets.
+// It is called for every delivery, so implementations backed by a remote store should cache.
+type SecretProvider interface {
+	Secrets(ctx context.Context) ([]Secret, error)
+}
+
+// StaticSecrets is a fixed set of secrets
+type StaticSecrets []Secret
+
+func (s StaticSecrets) Secrets(context.Context) ([]Secret, error) {
+	return s, nil
+}
+
+// StaticSecret is a provider for a single secret that never expires
+func StaticSecret(value string) SecretProvider {
+	return StaticSecrets{{ID: "default", Value: value}}
+}
+
+// VerifySignatureWithSecrets checks the signature against every secret active at now
+// and returns the one that matched.
+func VerifySignatureWithSecrets(payload []byte, signature string, secrets []Secret, now time.Time) (Secret, bool) {
+	for _, s := range secrets {
+		if s.ActiveAt(now) && VerifySignature(payload, signature, s.Value) {
+			return s, true
+		}
+	}
+	return Secret{}, false
+}
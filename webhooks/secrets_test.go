@@ -0,0 +1,115 @@
+package webhooks_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/webhooks"
+)
+
+func TestSecretActiveAt(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		secret webhooks.Secret
+		want   bool
+	}{
+		{"no window", webhooks.Secret{}, true},
+		{"started", webhooks.Secret{NotBefore: now.Add(-time.Hour)}, true},
+		{"starts exactly now", webhooks.Secret{NotBefore: now}, true},
+		{"not started", webhooks.Secret{NotBefore: now.Add(time.Hour)}, false},
+		{"not expired", webhooks.Secret{ExpiresAt: now.Add(time.Hour)}, true},
+		{"expires exactly now", webhooks.Secret{ExpiresAt: now}, false},
+		{"expired", webhooks.Secret{ExpiresAt: now.Add(-time.Hour)}, false},
+		{"inside window", webhooks.Secret{NotBefore: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.secret.ActiveAt(now); got != tt.want {
+				t.Errorf("ActiveAt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWithSecretsRotation(t *testing.T) {
+	now := time.Now()
+	secrets := webhooks.StaticSecrets{
+		{ID: "2024-q1", Value: "whsec_old", ExpiresAt: now.Add(time.Hour)},
+		{ID: "2024-q2", Value: "whsec_new", NotBefore: now.Add(-time.Hour)},
+		{ID: "2024-q3", Value: "whsec_next", NotBefore: now.Add(time.Hour)},
+		{ID: "2023-q4", Value: "whsec_retired", ExpiresAt: now.Add(-time.Hour)},
+	}
+	tests := []struct {
+		key    string
+		wantID string // Empty when the delivery must be rejected
+	}{
+		{"whsec_old", "2024-q1"},
+		{"whsec_new", "2024-q2"},
+		{"whsec_next", ""},
+		{"whsec_retired", ""},
+		{"whsec_unknown", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			body := eventBody("evt-1", webhooks.EventTypeJobCreated, `{}`)
+			event, matched, err := webhooks.ParseWithSecrets(signedRequest(body, tt.key), secrets)
+			if tt.wantID == "" {
+				if !errors.Is(err, webhooks.ErrInvalidSignature) || matched != nil {
+					t.Errorf("err = %v, matched %+v; want ErrInvalidSignature and no secret", err, matched)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWithSecrets: %v", err)
+			}
+			if event.ID != "evt-1" || matched == nil || matched.ID != tt.wantID {
+				t.Errorf("event %+v matched %+v, want secret %s", event, matched, tt.wantID)
+			}
+		})
+	}
+}
+
+// secretsFunc adapts a function to SecretProvider
+type secretsFunc func(ctx context.Context) ([]webhooks.Secret, error)
+
+func (f secretsFunc) Secrets(ctx context.Context) ([]webhooks.Secret, error) { return f(ctx) }
+
+func TestHandlerWithSecrets(t *testing.T) {
+	var provided []webhooks.Secret
+	var providerErr error
+	h := webhooks.NewHandlerWithSecrets(secretsFunc(func(context.Context) ([]webhooks.Secret, error) {
+		return provided, providerErr
+	}))
+	var matched []string
+	h.OnSecretMatched = func(_ *http.Request, s webhooks.Secret) { matched = append(matched, s.ID) }
+
+	provided = []webhooks.Secret{
+		{ID: "old", Value: "whsec_old", ExpiresAt: time.Now().Add(time.Hour)},
+		{ID: "new", Value: "whsec_new"},
+	}
+	body := eventBody("evt-1", webhooks.EventTypeJobCreated, `{}`)
+	for _, key := range []string{"whsec_new", "whsec_old"} {
+		if got := serve(h, signedRequest(body, key)); got != http.StatusOK {
+			t.Errorf("signed with %s: status %d, want 200", key, got)
+		}
+	}
+	if len(matched) != 2 || matched[0] != "new" || matched[1] != "old" {
+		t.Errorf("OnSecretMatched got %v, want [new old]", matched)
+	}
+
+	// Once the old secret is gone from the provider, its deliveries are rejected
+	provided = provided[1:]
+	if got := serve(h, signedRequest(body, "whsec_old")); got != http.StatusUnauthorized {
+		t.Errorf("retired secret: status %d, want 401", got)
+	}
+
+	// A provider failure is on our side, so VersaFleet should retry
+	providerErr = errors.New("vault sealed")
+	if got := serve(h, signedRequest(body, "whsec_new")); got != http.StatusInternalServerError {
+		t.Errorf("provider down: status %d, want 500", got)
+	}
+}
@@ -6,18 +6,25 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
+// SignatureHeader carries the hex HMAC-SHA256 of the request body
+const SignatureHeader = "X-Versafleet-Signature"
+
 // EventType represents the type of webhook event
 type EventType string
 
-const (
-	EventTypeJobCreated   EventType = "job.created"
-	EventTypeJobUpdated   EventType = "job.updated"
-	EventTypeTaskCompleted EventType = "task.completed"
-	// Add others
+var (
+	ErrMissingSignature = errors.New("missing signature header")
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrStaleEvent is returned when an event's created_at is outside the replay tolerance
+	ErrStaleEvent = errors.New("event timestamp outside tolerance")
+	// ErrDuplicate is returned, together with the event, for an event ID that was already delivered
+	ErrDuplicate = errors.New("duplicate event")
 )
 
 type Event struct {
@@ -27,35 +34,108 @@ type Event struct {
 	Data      json.RawMessage `json:"data"`
 }
 
-// Parse reads the request body, validates the signature, and returns the event
-func Parse(req *http.Request, secret string) (*Event, error) {
-	signature := req.Header.Get("X-Versafleet-Signature")
+// ParseOption configures the optional checks done by Parse
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	tolerance time.Duration
+	dedup     DedupStore
+	now       func() time.Time
+}
+
+// WithTolerance rejects events whose created_at is further than d from now with ErrStaleEvent,
+// so a captured delivery can't be replayed later. created_at is covered by the signature.
+func WithTolerance(d time.Duration) ParseOption {
+	return func(o *parseOptions) { o.tolerance = d }
+}
+
+// WithDedup records event IDs in store and reports already delivered events with ErrDuplicate
+func WithDedup(store DedupStore) ParseOption {
+	return func(o *parseOptions) { o.dedup = store }
+}
+
+// Parse reads the request body, validates the signature, and returns the event.
+//
+// With WithDedup, a repeated delivery returns the event together with ErrDuplicate,
+// so the caller can acknowledge it without processing it again.
+func Parse(req *http.Request, secret string, opts ...ParseOption) (*Event, error) {
+	event, _, err := ParseWithSecrets(req, StaticSecret(secret), opts...)
+	return event, err
+}
+
+// ParseWithSecrets is like Parse but verifies against every secret from provider that is
+// currently active, and returns the secret that matched. Use it to rotate secrets
+// without rejecting deliveries, and to see when an old secret stops being used.
+func ParseWithSecrets(req *http.Request, provider SecretProvider, opts ...ParseOption) (*Event, *Secret, error) {
+	o := parseOptions{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	signature := req.Header.Get(SignatureHeader)
 	if signature == "" {
-		return nil, errors.New("missing signature header")
+		return nil, nil, ErrMissingSignature
 	}
 
 	body, err := io.ReadAll(req.Body)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer req.Body.Close()
 
-	if !VerifySignature(body, signature, secret) {
-		return nil, errors.New("invalid signature")
+	secrets, err := provider.Secrets(req.Context())
+	if err != nil {
+		return nil, nil, &internalError{fmt.Errorf("webhooks: failed to load secrets: %w", err)}
+	}
+	matched, ok := VerifySignatureWithSecrets(body, signature, secrets, o.now())
+	if !ok {
+		return nil, nil, ErrInvalidSignature
 	}
 
 	var event Event
 	if err := json.Unmarshal(body, &event); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if o.tolerance > 0 {
+		createdAt, err := time.Parse(time.RFC3339, event.CreatedAt)
+		if err != nil {
+			return nil, nil, ErrStaleEvent
+		}
+		if age := o.now().Sub(createdAt); age > o.tolerance || age < -o.tolerance {
+			return nil, nil, ErrStaleEvent
+		}
+	}
+
+	if o.dedup != nil && event.ID != "" {
+		seen, err := o.dedup.MarkSeen(req.Context(), event.ID)
+		if err != nil {
+			return nil, nil, &internalError{fmt.Errorf("webhooks: dedup store failed: %w", err)}
+		}
+		if seen {
+			return &event, &matched, ErrDuplicate
+		}
 	}
 
-	return &event, nil
+	return &event, &matched, nil
 }
 
+// internalError marks failures on the receiving side (secret provider, dedup store),
+// as opposed to a bad delivery, so Handler can ask VersaFleet to retry
+type internalError struct{ err error }
+
+func (e *internalError) Error() string { return e.err.Error() }
+func (e *internalError) Unwrap() error { return e.err }
+
 // VerifySignature checks the HMAC-SHA256 signature
 func VerifySignature(payload []byte, signature, secret string) bool {
+	expectedSignature := Sign(payload, secret)
+	return hmac.Equal([]byte(expectedSignature), []byte(signature))
+}
+
+// Sign returns the hex HMAC-SHA256 signature of payload, as sent in the X-Versafleet-Signature header
+func Sign(payload []byte, secret string) string {
 	h := hmac.New(sha256.New, []byte(secret))
 	h.Write(payload)
-	expectedSignature := hex.EncodeToString(h.Sum(nil))
-	return hmac.Equal([]byte(expectedSignature), []byte(signature))
+	return hex.EncodeToString(h.Sum(nil))
 }
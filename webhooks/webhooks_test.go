@@ -0,0 +1,184 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/webhooks"
+)
+
+// eventAt is a delivery of a job.created event with the given created_at
+func eventAt(id, createdAt string) []byte {
+	body, _ := json.Marshal(webhooks.Event{ID: id, Type: webhooks.EventTypeJobCreated, CreatedAt: createdAt, Data: json.RawMessage(`{"job":{"id":1}}`)})
+	return body
+}
+
+func TestParseSignature(t *testing.T) {
+	body := eventBody("evt-1", webhooks.EventTypeJobCreated, `{"job":{"id":1}}`)
+	tampered := signedRequest(body, secret)
+	tampered.Body = signedRequest(eventBody("evt-1", webhooks.EventTypeJobCreated, `{"job":{"id":2}}`), "").Body
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want error
+	}{
+		{"valid", signedRequest(body, secret), nil},
+		{"missing", signedRequest(body, ""), webhooks.ErrMissingSignature},
+		{"wrong secret", signedRequest(body, "whsec_other"), webhooks.ErrInvalidSignature},
+		{"tampered body", tampered, webhooks.ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := webhooks.Parse(tt.req, secret)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Parse err = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && (event.ID != "evt-1" || event.Type != webhooks.EventTypeJobCreated) {
+				t.Errorf("Parse = %+v", event)
+			}
+			if tt.want != nil && event != nil {
+				t.Errorf("Parse returned %+v with a rejected signature", event)
+			}
+		})
+	}
+}
+
+func TestParseTolerance(t *testing.T) {
+	now := time.Now().UTC()
+	tests := []struct {
+		name      string
+		createdAt string
+		want      error
+	}{
+		{"recent", now.Add(-time.Minute).Format(time.RFC3339), nil},
+		{"too old", now.Add(-10 * time.Minute).Format(time.RFC3339), webhooks.ErrStaleEvent},
+		{"too far ahead", now.Add(10 * time.Minute).Format(time.RFC3339), webhooks.ErrStaleEvent},
+		{"unparsable", "yesterday", webhooks.ErrStaleEvent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := eventAt("evt-1", tt.createdAt)
+			if _, err := webhooks.Parse(signedRequest(body, secret), secret, webhooks.WithTolerance(5*time.Minute)); !errors.Is(err, tt.want) {
+				t.Errorf("Parse err = %v, want %v", err, tt.want)
+			}
+			// Without a tolerance the timestamp isn't checked
+			if _, err := webhooks.Parse(signedRequest(body, secret), secret); err != nil {
+				t.Errorf("Parse without tolerance: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseDedup(t *testing.T) {
+	store := webhooks.NewMemoryDedupStore(100, time.Hour)
+	body := eventBody("evt-1", webhooks.EventTypeJobCreated, `{"job":{"id":1}}`)
+
+	if _, err := webhooks.Parse(signedRequest(body, secret), secret, webhooks.WithDedup(store)); err != nil {
+		t.Fatalf("first delivery: %v", err)
+	}
+	event, err := webhooks.Parse(signedRequest(body, secret), secret, webhooks.WithDedup(store))
+	if !errors.Is(err, webhooks.ErrDuplicate) {
+		t.Fatalf("second delivery err = %v, want ErrDuplicate", err)
+	}
+	if event == nil || event.ID != "evt-1" {
+		t.Errorf("duplicate returned event %+v, want evt-1 so it can be acknowledged", event)
+	}
+
+	other := eventBody("evt-2", webhooks.EventTypeJobCreated, `{"job":{"id":1}}`)
+	if _, err := webhooks.Parse(signedRequest(other, secret), secret, webhooks.WithDedup(store)); err != nil {
+		t.Errorf("another event: %v", err)
+	}
+
+	// A delivery with a bad signature must not burn the ID
+	forged := eventBody("evt-3", webhooks.EventTypeJobCreated, `{}`)
+	webhooks.Parse(signedRequest(forged, "whsec_other"), secret, webhooks.WithDedup(store))
+	if _, err := webhooks.Parse(signedRequest(forged, secret), secret, webhooks.WithDedup(store)); err != nil {
+		t.Errorf("genuine delivery after a forged one: %v", err)
+	}
+}
+
+func TestMemoryDedupStore(t *testing.T) {
+	ctx := context.Background()
+	seen := func(s *webhooks.MemoryDedupStore, id string) bool {
+		t.Helper()
+		ok, err := s.MarkSeen(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ok
+	}
+
+	s := webhooks.NewMemoryDedupStore(2, 0)
+	if seen(s, "a") || seen(s, "b") || !seen(s, "a") {
+		t.Fatal("first sightings reported as seen, or repeat as new")
+	}
+	// "b" is now the least recently seen, so "c" evicts it
+	if seen(s, "c") || seen(s, "b") {
+		t.Error("capacity didn't evict the least recently seen ID")
+	}
+
+	if err := s.Forget(ctx, "c"); err != nil {
+		t.Fatal(err)
+	}
+	if seen(s, "c") {
+		t.Error("forgotten ID still seen")
+	}
+
+	s = webhooks.NewMemoryDedupStore(10, 20*time.Millisecond)
+	seen(s, "a")
+	time.Sleep(40 * time.Millisecond)
+	if seen(s, "a") {
+		t.Error("ID kept past its TTL")
+	}
+}
+
+// failingStore is a DedupStore that is down
+type failingStore struct{}
+
+func (failingStore) MarkSeen(context.Context, string) (bool, error) {
+	return false, errors.New("redis down")
+}
+func (failingStore) Forget(context.Context, string) error { return errors.New("redis down") }
+
+func TestHandlerDedupAndTolerance(t *testing.T) {
+	calls := 0
+	fail := true
+	h := webhooks.NewHandler(secret).OnJobCreated(func(context.Context, *webhooks.JobEvent) error {
+		calls++
+		if fail {
+			return errors.New("db down")
+		}
+		return nil
+	})
+	h.Dedup = webhooks.NewMemoryDedupStore(100, time.Hour)
+	h.Tolerance = 5 * time.Minute
+	body := eventBody("evt-1", webhooks.EventTypeJobCreated, `{"job":{"id":1}}`)
+
+	// A failed callback forgets the ID, so the retry is processed
+	if got := serve(h, signedRequest(body, secret)); got != http.StatusInternalServerError {
+		t.Errorf("failing callback: status %d, want 500", got)
+	}
+	fail = false
+	if got := serve(h, signedRequest(body, secret)); got != http.StatusOK || calls != 2 {
+		t.Errorf("retry: status %d after %d calls, want 200 after 2", got, calls)
+	}
+	// Once processed, a redelivery is acknowledged without calling back
+	if got := serve(h, signedRequest(body, secret)); got != http.StatusOK || calls != 2 {
+		t.Errorf("duplicate: status %d after %d calls, want 200 after 2", got, calls)
+	}
+
+	stale := eventAt("evt-2", time.Now().Add(-time.Hour).UTC().Format(time.RFC3339))
+	if got := serve(h, signedRequest(stale, secret)); got != http.StatusBadRequest {
+		t.Errorf("stale event: status %d, want 400", got)
+	}
+
+	h.Dedup = failingStore{}
+	if got := serve(h, signedRequest(eventBody("evt-3", webhooks.EventTypeJobCreated, `{}`), secret)); got != http.StatusInternalServerError {
+		t.Errorf("dedup store down: status %d, want 500 so the delivery is retried", got)
+	}
+}
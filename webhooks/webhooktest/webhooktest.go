@@ -0,0 +1,232 @@
+// Package webhooktest builds correctly signed VersaFleet webhook deliveries and posts
+// them to a local endpoint, for developing and testing webhook consumers without
+// real VersaFleet traffic.
+package webhooktest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	mrand "math/rand/v2"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/model"
+	"github.com/Willias7788/go-versafleet-sdk/webhooks"
+)
+
+// NewEvent builds an event with a random ID and created_at set to now.
+// data is marshalled as the event payload.
+func NewEvent(t webhooks.EventType, data interface{}) (*webhooks.Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("webhooktest: failed to encode %s payload: %w", t, err)
+	}
+	return &webhooks.Event{
+		ID:        newID(),
+		Type:      t,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Data:      raw,
+	}, nil
+}
+
+// TaskEvent builds a task.* event carrying task
+func TaskEvent(t webhooks.EventType, task model.Task) (*webhooks.Event, error) {
+	return NewEvent(t, webhooks.TaskEvent{Task: task})
+}
+
+// JobEvent builds a job.* event carrying job
+func JobEvent(t webhooks.EventType, job model.Job) (*webhooks.Event, error) {
+	return NewEvent(t, webhooks.JobEvent{Job: job})
+}
+
+// LoadFixture reads an event from a JSON file. A missing id or created_at is filled in,
+// so fixtures only need a type and data.
+func LoadFixture(path string) (*webhooks.Event, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("webhooktest: failed to read fixture: %w", err)
+	}
+	var event webhooks.Event
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return nil, fmt.Errorf("webhooktest: failed to decode fixture %s: %w", path, err)
+	}
+	if event.Type == "" {
+		return nil, fmt.Errorf("webhooktest: fixture %s has no type", path)
+	}
+	if event.ID == "" {
+		event.ID = newID()
+	}
+	if event.CreatedAt == "" {
+		event.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	return &event, nil
+}
+
+// Delivery is a signed webhook request body
+type Delivery struct {
+	Event     *webhooks.Event
+	Body      []byte
+	Signature string
+}
+
+// NewDelivery encodes and signs an event with the same HMAC that webhooks.VerifySignature checks
+func NewDelivery(event *webhooks.Event, secret string) (*Delivery, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("webhooktest: failed to encode event: %w", err)
+	}
+	return &Delivery{Event: event, Body: body, Signature: webhooks.Sign(body, secret)}, nil
+}
+
+// Request builds the POST request for the delivery
+func (d *Delivery) Request(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(d.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhooks.SignatureHeader, d.Signature)
+	return req, nil
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "evt_" + hex.EncodeToString(b)
+}
+
+// Result is the outcome of one POST made by a Sender
+type Result struct {
+	EventID    string
+	EventType  webhooks.EventType
+	Attempt    int // 1 for the first try of a delivery
+	Duplicate  bool
+	StatusCode int
+	Body       string
+	Err        error
+}
+
+// Sender posts signed deliveries to a webhook endpoint
+type Sender struct {
+	URL    string
+	Secret string
+	Client *http.Client // Defaults to http.DefaultClient
+
+	// Retries resends a delivery that didn't get a 2xx, up to this many times, RetryWait apart
+	Retries   int
+	RetryWait time.Duration
+
+	// Duplicates sends every delivery this many extra times, like VersaFleet's at-least-once delivery
+	Duplicates int
+	// BadSignature signs with a corrupted signature, to check that deliveries are rejected
+	BadSignature bool
+	// Shuffle sends events in random order. Duplicates still come after their original,
+	// at a random point later in the queue.
+	Shuffle bool
+}
+
+// Send posts every event and returns one result per POST made
+func (s *Sender) Send(ctx context.Context, events ...*webhooks.Event) ([]Result, error) {
+	type queued struct {
+		delivery  *Delivery
+		duplicate bool
+	}
+
+	deliveries := make([]*Delivery, 0, len(events))
+	for _, event := range events {
+		d, err := NewDelivery(event, s.Secret)
+		if err != nil {
+			return nil, err
+		}
+		if s.BadSignature {
+			d.Signature = corrupt(d.Signature)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if s.Shuffle {
+		mrand.Shuffle(len(deliveries), func(i, j int) { deliveries[i], deliveries[j] = deliveries[j], deliveries[i] })
+	}
+
+	queue := make([]queued, 0, len(deliveries)*(s.Duplicates+1))
+	for _, d := range deliveries {
+		queue = append(queue, queued{delivery: d})
+	}
+	for _, d := range deliveries {
+		// Duplicates go right after the original, or anywhere after it when shuffling
+		at := slices.IndexFunc(queue, func(q queued) bool { return q.delivery == d }) + 1
+		for i := 0; i < s.Duplicates; i++ {
+			pos := at
+			if s.Shuffle {
+				pos += mrand.IntN(len(queue) - at + 1)
+			}
+			queue = slices.Insert(queue, pos, queued{delivery: d, duplicate: true})
+		}
+	}
+
+	var results []Result
+	for _, q := range queue {
+		for attempt := 1; attempt <= s.Retries+1; attempt++ {
+			if attempt > 1 && s.RetryWait > 0 {
+				select {
+				case <-time.After(s.RetryWait):
+				case <-ctx.Done():
+					return results, ctx.Err()
+				}
+			}
+			res := s.post(ctx, q.delivery)
+			res.Attempt = attempt
+			res.Duplicate = q.duplicate
+			results = append(results, res)
+			if res.Err == nil && res.StatusCode >= 200 && res.StatusCode < 300 {
+				break
+			}
+			if ctx.Err() != nil {
+				return results, ctx.Err()
+			}
+		}
+	}
+	return results, nil
+}
+
+func (s *Sender) post(ctx context.Context, d *Delivery) Result {
+	res := Result{EventID: d.Event.ID, EventType: d.Event.Type}
+	req, err := d.Request(ctx, s.URL)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	res.StatusCode = resp.StatusCode
+	res.Body = string(body)
+	return res
+}
+
+// corrupt flips the last hex digit so the signature no longer verifies
+func corrupt(sig string) string {
+	if sig == "" {
+		return "0"
+	}
+	last := sig[len(sig)-1]
+	flipped := byte('0')
+	if last == '0' {
+		flipped = '1'
+	}
+	return sig[:len(sig)-1] + string(flipped)
+}
@@ -0,0 +1,170 @@
+package webhooktest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Willias7788/go-versafleet-sdk/webhooks"
+	"github.com/Willias7788/go-versafleet-sdk/webhooks/webhooktest"
+)
+
+func newEvents(t *testing.T, n int) []*webhooks.Event {
+	t.Helper()
+	events := make([]*webhooks.Event, n)
+	for i := range events {
+		e, err := webhooktest.NewEvent(webhooks.EventTypeJobCreated, map[string]int{"n": i})
+		if err != nil {
+			t.Fatal(err)
+		}
+		events[i] = e
+	}
+	return events
+}
+
+const secret = "s3cret"
+
+// receiver checks every delivery with webhooks.Parse, answering 401 to a bad signature and
+// then the next of statuses, or 200 once they run out
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	parsed   []string // Event IDs that passed Parse
+	rejected []error
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	event, err := webhooks.Parse(r, secret)
+	if err != nil {
+		rc.rejected = append(rc.rejected, err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	rc.parsed = append(rc.parsed, event.ID)
+	if len(rc.statuses) > 0 {
+		w.WriteHeader(rc.statuses[0])
+		rc.statuses = rc.statuses[1:]
+	}
+}
+
+func TestSenderDuplicatesFollowOriginal(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	for _, shuffle := range []bool{false, true} {
+		events := newEvents(t, 5)
+		sender := &webhooktest.Sender{URL: srv.URL, Secret: secret, Duplicates: 2, Shuffle: shuffle}
+		// Shuffling is random, so give a misplaced duplicate plenty of chances to show up
+		for run := 0; run < 20; run++ {
+			results, err := sender.Send(context.Background(), events...)
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			if len(results) != 15 {
+				t.Fatalf("got %d results, want 15", len(results))
+			}
+
+			seen := make(map[string]int)
+			for i, res := range results {
+				if res.Err != nil || res.StatusCode != http.StatusOK {
+					t.Fatalf("result %d for %s: status %d, err %v", i, res.EventID, res.StatusCode, res.Err)
+				}
+				if res.Duplicate != (seen[res.EventID] > 0) {
+					t.Fatalf("shuffle=%v: result %d for %s has Duplicate=%v after %d sends", shuffle, i, res.EventID, res.Duplicate, seen[res.EventID])
+				}
+				seen[res.EventID]++
+			}
+			for _, e := range events {
+				if seen[e.ID] != 3 {
+					t.Errorf("event %s sent %d times, want 3", e.ID, seen[e.ID])
+				}
+			}
+
+			if !shuffle {
+				for i, res := range results {
+					if res.EventID != events[i/3].ID {
+						t.Fatalf("unshuffled result %d is %s, want %s", i, res.EventID, events[i/3].ID)
+					}
+				}
+			}
+		}
+	}
+
+	if len(rc.rejected) > 0 {
+		t.Errorf("receiver rejected deliveries: %v", rc.rejected)
+	}
+	if len(rc.parsed) != 2*20*15 {
+		t.Errorf("receiver parsed %d deliveries, want %d", len(rc.parsed), 2*20*15)
+	}
+}
+
+func TestSenderBadSignature(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	sender := &webhooktest.Sender{URL: srv.URL, Secret: secret, BadSignature: true, Duplicates: 1}
+	results, err := sender.Send(context.Background(), newEvents(t, 3)...)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(results) != 6 {
+		t.Fatalf("got %d results, want 6", len(results))
+	}
+	for i, res := range results {
+		if res.StatusCode != http.StatusUnauthorized {
+			t.Errorf("result %d: status %d, want 401", i, res.StatusCode)
+		}
+	}
+	if len(rc.parsed) != 0 {
+		t.Errorf("receiver accepted %v", rc.parsed)
+	}
+	for _, err := range rc.rejected {
+		if !errors.Is(err, webhooks.ErrInvalidSignature) {
+			t.Errorf("Parse err = %v, want ErrInvalidSignature", err)
+		}
+	}
+}
+
+func TestSenderRetries(t *testing.T) {
+	// The first delivery fails twice and then succeeds, the second is never accepted
+	rc := &receiver{statuses: []int{500, 503, 200, 500, 500, 500}}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	events := newEvents(t, 2)
+	sender := &webhooktest.Sender{URL: srv.URL, Secret: secret, Retries: 2, RetryWait: time.Millisecond}
+	results, err := sender.Send(context.Background(), events...)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	want := []struct {
+		id      string
+		attempt int
+		status  int
+	}{
+		{events[0].ID, 1, 500}, {events[0].ID, 2, 503}, {events[0].ID, 3, 200},
+		{events[1].ID, 1, 500}, {events[1].ID, 2, 500}, {events[1].ID, 3, 500},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		res := results[i]
+		if res.EventID != w.id || res.Attempt != w.attempt || res.StatusCode != w.status || res.Duplicate {
+			t.Errorf("result %d = %s attempt %d status %d, want %s attempt %d status %d", i, res.EventID, res.Attempt, res.StatusCode, w.id, w.attempt, w.status)
+		}
+	}
+	// Every retry is a valid delivery of the same event
+	if len(rc.rejected) != 0 || len(rc.parsed) != 6 {
+		t.Errorf("receiver parsed %d and rejected %v, want 6 parsed", len(rc.parsed), rc.rejected)
+	}
+}